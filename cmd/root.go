@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"azdo-dash/ui"
+	"azdo-dash/ui/diffview"
+	"azdo-dash/ui/markdown"
 	"fmt"
 	slog "log"
 	"os"
@@ -118,8 +120,8 @@ func init() {
 		// see https://github.com/charmbracelet/lipgloss/issues/73
 		lipgloss.SetHasDarkBackground(termenv.HasDarkBackground())
 
-		// TODO: markdown not yet implemented
-		//markdown.InitializeMarkdownStyle(termenv.HasDarkBackground())
+		markdown.InitializeMarkdownStyle(termenv.HasDarkBackground())
+		diffview.InitializeHighlightStyle(termenv.HasDarkBackground())
 
 		model, logger := createModel(cfgFile, debug)
 		if logger != nil {
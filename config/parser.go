@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
+	"time"
 )
 
 const DashDir = "azdo-dash"
@@ -20,9 +21,72 @@ const DEFAULT_XDG_CONFIG_DIRNAME = ".config"
 var validate *validator.Validate
 
 type Config struct {
+	BaseURL             string           `yaml:"base_url" validate:"omitempty,url"`
 	OrgName             string           `yaml:"org_name" validate:"required"`
+	APIVersion          string           `yaml:"api_version"`
 	Projects            []ConfigProjects `yaml:"projects"`
 	PersonalAccessToken string           `yaml:"personal_access_token" validate:"required"`
+	Defaults            ConfigDefaults   `yaml:"defaults"`
+	Sections            []SectionConfig  `yaml:"sections" validate:"dive"`
+	Checkout            CheckoutConfig   `yaml:"checkout"`
+}
+
+// CheckoutConfig configures checking out the source branches of pull
+// requests. RepoPaths maps repository ids or names to local clones. Clones
+// of other repositories are looked for by their remote URL in SearchDirs and
+// their subdirectories. With Worktree branches are checked out into a git
+// worktree next to the clone by default.
+type CheckoutConfig struct {
+	RepoPaths  map[string]string `yaml:"repo_paths"`
+	SearchDirs []string          `yaml:"search_dirs"`
+	Worktree   bool              `yaml:"worktree"`
+}
+
+const (
+	PrsSectionType       = "prs"
+	WorkItemsSectionType = "workitems"
+)
+
+// SectionConfig configures a section of the dashboard. Sections show pull
+// requests unless Type is WorkItemsSectionType, then they show the work items
+// returned by the WIQL query. Sections without projects show the pull
+// requests of the top level projects, work item queries run in the first
+// project.
+type SectionConfig struct {
+	Title    string           `yaml:"title" validate:"required"`
+	Type     string           `yaml:"type" validate:"omitempty,oneof=prs workitems"`
+	Projects []ConfigProjects `yaml:"projects"`
+	Filters  PrFilters        `yaml:"filters"`
+	WIQL     string           `yaml:"wiql" validate:"required_if=Type workitems"`
+}
+
+// PrFilters are applied by the server when fetching a section's pull
+// requests. Creator and Reviewer take an identity id, or "@me" for the user
+// of the personal access token. Since and Until are relative to now.
+type PrFilters struct {
+	Status       string        `yaml:"status" validate:"omitempty,oneof=active completed abandoned all"`
+	Creator      string        `yaml:"creator"`
+	Reviewer     string        `yaml:"reviewer"`
+	SourceBranch string        `yaml:"source_branch"`
+	TargetBranch string        `yaml:"target_branch"`
+	Since        time.Duration `yaml:"since" validate:"gte=0"`
+	Until        time.Duration `yaml:"until" validate:"gte=0"`
+	TimeRange    string        `yaml:"time_range" validate:"omitempty,oneof=created closed"`
+}
+
+const CurrentUserAlias = "@me"
+
+type ConfigDefaults struct {
+	Concurrency    int           `yaml:"concurrency" validate:"gte=0"`
+	PrsLimit       int           `yaml:"prs_limit" validate:"gte=0"`
+	WorkItemsLimit int           `yaml:"work_items_limit" validate:"gte=0"`
+	PageSize       int           `yaml:"page_size" validate:"gte=0"`
+	Preview        PreviewConfig `yaml:"preview"`
+}
+
+type PreviewConfig struct {
+	Open  bool `yaml:"open"`
+	Width int  `yaml:"width" validate:"gte=0"`
 }
 
 type ConfigProjects struct {
@@ -38,11 +102,33 @@ type configError struct {
 
 type ConfigParser struct{}
 
+// GetSections returns the configured sections, or a single section showing
+// the pull requests of all projects if there are none.
+func (cfg Config) GetSections() []SectionConfig {
+	if len(cfg.Sections) > 0 {
+		return cfg.Sections
+	}
+
+	return []SectionConfig{{Title: "Pull Requests"}}
+}
+
 func (parser ConfigParser) getDefaultConfig() Config {
 	return Config{
+		BaseURL:             "https://dev.azure.com",
 		OrgName:             "",
+		APIVersion:          "7.1",
 		Projects:            []ConfigProjects{},
 		PersonalAccessToken: "",
+		Defaults: ConfigDefaults{
+			Concurrency:    8,
+			PrsLimit:       20,
+			WorkItemsLimit: 200,
+			PageSize:       100,
+			Preview: PreviewConfig{
+				Open:  true,
+				Width: 60,
+			},
+		},
 	}
 }
 
@@ -2,11 +2,26 @@ package constants
 
 import tea "github.com/charmbracelet/bubbletea"
 
+// TaskFinishedMsg delivers the message of a finished task to its section.
+// A FinishedText replaces the one the task was started with.
 type TaskFinishedMsg struct {
-	TaskId      string
+	TaskId       string
+	SectionId    int
+	SectionType  string
+	Err          error
+	Msg          tea.Msg
+	FinishedText string
+}
+
+// TaskFinishedTextMsg is returned by tasks that only know how to describe
+// their result once they finished. It becomes the FinishedText of the task.
+type TaskFinishedTextMsg string
+
+// SectionMsg delivers Msg to a section without a task, for background
+// updates that should not show in the footer.
+type SectionMsg struct {
 	SectionId   int
 	SectionType string
-	Err         error
 	Msg         tea.Msg
 }
 
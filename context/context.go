@@ -2,14 +2,25 @@ package context
 
 import (
 	"azdo-dash/config"
+	"azdo-dash/data"
+	"azdo-dash/state"
 	tea "github.com/charmbracelet/bubbletea"
 	"time"
 )
 
 type ProgramContext struct {
-	Config     *config.Config
-	ConfigPath string
-	StartTask  func(task Task) tea.Cmd
+	Config            *config.Config
+	ConfigPath        string
+	Client            *data.Client
+	User              *data.User
+	Visits            *state.Visits
+	ScreenWidth       int
+	ScreenHeight      int
+	MainContentWidth  int
+	MainContentHeight int
+	PreviewOpen       bool
+	PreviewWidth      int
+	StartTask         func(task Task) tea.Cmd
 }
 
 type State = int
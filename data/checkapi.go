@@ -0,0 +1,201 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+const (
+	CheckStatePassed  = "passed"
+	CheckStateFailed  = "failed"
+	CheckStatePending = "pending"
+	// CheckStateMissing is the state of a check that has not run yet, e.g. a
+	// build that was never queued or expired.
+	CheckStateMissing = "missing"
+	// CheckStateUnknown is the state of a pull request whose checks could not
+	// be fetched.
+	CheckStateUnknown = "unknown"
+)
+
+// Check is a branch policy evaluated for a pull request, or a status posted
+// to it by a build or an external service.
+type Check struct {
+	Name        string
+	State       string
+	IsRequired  bool
+	Description string
+}
+
+type PolicyEvaluationsResponse struct {
+	Value []PolicyEvaluationResponse `json:"value"`
+}
+
+type PolicyEvaluationResponse struct {
+	EvaluationID  string                      `json:"evaluationId"`
+	Status        string                      `json:"status"`
+	Configuration PolicyConfigurationResponse `json:"configuration"`
+}
+
+type PolicyConfigurationResponse struct {
+	IsBlocking bool               `json:"isBlocking"`
+	IsEnabled  bool               `json:"isEnabled"`
+	Type       PolicyTypeResponse `json:"type"`
+	Settings   map[string]any     `json:"settings"`
+}
+
+type PolicyTypeResponse struct {
+	DisplayName string `json:"displayName"`
+}
+
+type PullRequestStatusesResponse struct {
+	Value []PullRequestStatusResponse `json:"value"`
+}
+
+type PullRequestStatusResponse struct {
+	ID          int                   `json:"id"`
+	State       string                `json:"state"`
+	Description string                `json:"description"`
+	Context     StatusContextResponse `json:"context"`
+}
+
+type StatusContextResponse struct {
+	Name  string `json:"name"`
+	Genre string `json:"genre"`
+}
+
+var policyStates = map[string]string{
+	"approved": CheckStatePassed,
+	"rejected": CheckStateFailed,
+	"broken":   CheckStateFailed,
+	"running":  CheckStatePending,
+	"queued":   CheckStateMissing,
+}
+
+var statusStates = map[string]string{
+	"succeeded": CheckStatePassed,
+	"failed":    CheckStateFailed,
+	"error":     CheckStateFailed,
+	"pending":   CheckStatePending,
+	"notSet":    CheckStateMissing,
+}
+
+// FetchChecks fetches the policy evaluations and the statuses of a pull
+// request. Policies and statuses that do not apply are left out.
+func (c *Client) FetchChecks(ctx context.Context, pr PullRequestData) ([]Check, error) {
+	policies, err := c.fetchPolicyChecks(ctx, pr)
+	if err != nil {
+		return nil, fmt.Errorf("fetching policy evaluations: %w", err)
+	}
+
+	statuses, err := c.fetchStatusChecks(ctx, pr)
+	if err != nil {
+		return nil, fmt.Errorf("fetching statuses: %w", err)
+	}
+
+	return append(policies, statuses...), nil
+}
+
+func (c *Client) fetchPolicyChecks(ctx context.Context, pr PullRequestData) ([]Check, error) {
+	query := url.Values{}
+	query.Set("api-version", c.previewVersion())
+	query.Set("artifactId", fmt.Sprintf("vstfs:///CodeReview/CodeReviewId/%s/%d", pr.ProjectID, pr.ID))
+	path := fmt.Sprintf("%s/_apis/policy/evaluations", url.PathEscape(pr.ProjectID))
+
+	var response PolicyEvaluationsResponse
+	err := c.do(ctx, "GET", c.url(path, query), nil, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	checks := make([]Check, 0, len(response.Value))
+	for _, evaluation := range response.Value {
+		state, ok := policyStates[evaluation.Status]
+		if !ok || !evaluation.Configuration.IsEnabled {
+			continue
+		}
+
+		name := evaluation.Configuration.Type.DisplayName
+		// Build policies carry the name they were given in the settings.
+		if displayName, ok := evaluation.Configuration.Settings["displayName"].(string); ok && displayName != "" {
+			name = fmt.Sprintf("%s: %s", name, displayName)
+		}
+
+		checks = append(checks, Check{
+			Name:       name,
+			State:      state,
+			IsRequired: evaluation.Configuration.IsBlocking,
+		})
+	}
+
+	return checks, nil
+}
+
+func (c *Client) fetchStatusChecks(ctx context.Context, pr PullRequestData) ([]Check, error) {
+	var response PullRequestStatusesResponse
+	err := c.do(ctx, "GET", c.url(pullRequestPath(pr)+"/statuses", nil), nil, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	// A status is posted again for every iteration, only the latest one of
+	// each context counts.
+	latest := map[string]PullRequestStatusResponse{}
+	var names []string
+	for _, status := range response.Value {
+		name := status.Context.Name
+		if status.Context.Genre != "" {
+			name = fmt.Sprintf("%s/%s", status.Context.Genre, name)
+		}
+
+		previous, ok := latest[name]
+		if !ok {
+			names = append(names, name)
+		}
+		if !ok || status.ID > previous.ID {
+			latest[name] = status
+		}
+	}
+
+	checks := make([]Check, 0, len(names))
+	for _, name := range names {
+		status := latest[name]
+		state, ok := statusStates[status.State]
+		if !ok {
+			continue
+		}
+		checks = append(checks, Check{
+			Name:        name,
+			State:       state,
+			Description: status.Description,
+		})
+	}
+
+	return checks, nil
+}
+
+// ChecksState sums up checks in a single state. Failing checks outweigh
+// missing ones, which outweigh pending ones. Optional checks are only
+// reported if there are no required checks, so optional checks that fail,
+// wait or have not run do not hold back a pull request whose required checks
+// passed. It returns "" if there are no checks.
+func ChecksState(checks []Check) string {
+	required := map[string]bool{}
+	optional := map[string]bool{}
+	for _, check := range checks {
+		if check.IsRequired {
+			required[check.State] = true
+		} else {
+			optional[check.State] = true
+		}
+	}
+
+	for _, has := range []map[string]bool{required, optional} {
+		for _, state := range []string{CheckStateFailed, CheckStateMissing, CheckStatePending, CheckStatePassed} {
+			if has[state] {
+				return state
+			}
+		}
+	}
+	return ""
+}
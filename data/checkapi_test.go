@@ -0,0 +1,41 @@
+package data
+
+import (
+	"testing"
+)
+
+func TestChecksState(t *testing.T) {
+	required := func(state string) Check {
+		return Check{Name: "required " + state, State: state, IsRequired: true}
+	}
+	optional := func(state string) Check {
+		return Check{Name: "optional " + state, State: state}
+	}
+
+	tests := []struct {
+		name   string
+		checks []Check
+		want   string
+	}{
+		{"no checks", nil, ""},
+		{"required passed", []Check{required(CheckStatePassed)}, CheckStatePassed},
+		{"required failed", []Check{required(CheckStatePassed), required(CheckStateFailed)}, CheckStateFailed},
+		{"failed outweighs missing", []Check{required(CheckStateMissing), required(CheckStateFailed)}, CheckStateFailed},
+		{"missing outweighs pending", []Check{required(CheckStatePending), required(CheckStateMissing)}, CheckStateMissing},
+		{"pending outweighs passed", []Check{required(CheckStatePassed), required(CheckStatePending)}, CheckStatePending},
+		{"optional failed", []Check{required(CheckStatePassed), optional(CheckStateFailed)}, CheckStatePassed},
+		{"optional pending", []Check{required(CheckStatePassed), optional(CheckStatePending)}, CheckStatePassed},
+		{"optional missing", []Check{required(CheckStatePassed), optional(CheckStateMissing)}, CheckStatePassed},
+		{"required pending with optional failed", []Check{required(CheckStatePending), optional(CheckStateFailed)}, CheckStatePending},
+		{"only optional checks", []Check{optional(CheckStatePassed), optional(CheckStatePending)}, CheckStatePending},
+		{"only optional failed", []Check{optional(CheckStateFailed), optional(CheckStateMissing)}, CheckStateFailed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := ChecksState(test.checks); got != test.want {
+				t.Errorf("ChecksState() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
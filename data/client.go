@@ -0,0 +1,217 @@
+package data
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const DefaultBaseURL = "https://dev.azure.com"
+
+const DefaultAPIVersion = "7.1"
+
+// AuthProvider adds credentials to every request sent by a Client.
+type AuthProvider interface {
+	Authorize(req *http.Request) error
+}
+
+type PersonalAccessTokenAuth struct {
+	Token string
+}
+
+func (a PersonalAccessTokenAuth) Authorize(req *http.Request) error {
+	auth := "any:" + a.Token
+	encodedAuth := base64.StdEncoding.EncodeToString([]byte(auth))
+	req.Header.Set("Authorization", "Basic "+encodedAuth)
+	return nil
+}
+
+// Client talks to the REST API of an Azure DevOps organization or of an
+// Azure DevOps Server collection. BaseURL is the server root, OrgName the
+// organization or collection name.
+type Client struct {
+	BaseURL    string
+	OrgName    string
+	Auth       AuthProvider
+	HTTPClient *http.Client
+	APIVersion string
+}
+
+// NewClient returns a client for the organization at baseURL. An empty
+// baseURL or apiVersion fall back to DefaultBaseURL and DefaultAPIVersion.
+func NewClient(baseURL string, orgName string, apiVersion string, auth AuthProvider) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if apiVersion == "" {
+		apiVersion = DefaultAPIVersion
+	}
+
+	return &Client{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		OrgName:    orgName,
+		Auth:       auth,
+		HTTPClient: &http.Client{},
+		APIVersion: apiVersion,
+	}
+}
+
+// previewVersion returns the api-version of resources that are still in
+// preview at the client's APIVersion, e.g. "7.1-preview.1".
+func (c *Client) previewVersion() string {
+	return c.APIVersion + "-preview.1"
+}
+
+// url builds the address of an API resource below the organization. The
+// api-version query parameter defaults to the client's APIVersion.
+func (c *Client) url(path string, query url.Values) string {
+	if query == nil {
+		query = url.Values{}
+	}
+	if !query.Has("api-version") {
+		query.Set("api-version", c.APIVersion)
+	}
+
+	return fmt.Sprintf(
+		"%s/%s/%s?%s",
+		c.BaseURL,
+		url.PathEscape(c.OrgName),
+		strings.TrimPrefix(path, "/"),
+		query.Encode(),
+	)
+}
+
+// webURL builds the address of a page of the web portal below the
+// organization.
+func (c *Client) webURL(segments ...string) string {
+	escaped := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		escaped = append(escaped, url.PathEscape(segment))
+	}
+
+	return fmt.Sprintf(
+		"%s/%s/%s",
+		c.BaseURL,
+		url.PathEscape(c.OrgName),
+		strings.Join(escaped, "/"),
+	)
+}
+
+// do sends a request with an optional JSON body and decodes the JSON
+// response into result unless result is nil. A result of type *[]byte
+// receives the response body as is.
+func (c *Client) do(ctx context.Context, method string, url string, body any, result any) error {
+	return c.send(ctx, method, url, "application/json", body, result)
+}
+
+// doPatch sends a PATCH request with a body of json-patch operations, like
+// do.
+func (c *Client) doPatch(ctx context.Context, url string, operations any, result any) error {
+	return c.send(ctx, "PATCH", url, "application/json-patch+json", operations, result)
+}
+
+// send sends a request with an optional body encoded as JSON of contentType.
+func (c *Client) send(
+	ctx context.Context,
+	method string,
+	url string,
+	contentType string,
+	body any,
+	result any,
+) error {
+	var reqBody io.Reader
+	if body != nil {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encoding request: %w", err)
+		}
+		reqBody = bytes.NewReader(bodyBytes)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	if c.Auth != nil {
+		if err = c.Auth.Authorize(req); err != nil {
+			return fmt.Errorf("authorizing request: %w", err)
+		}
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newResponseError(resp)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if raw, ok := result.(*[]byte); ok {
+		*raw, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("reading response: %w", err)
+		}
+		return nil
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(result)
+	if err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+
+	return nil
+}
+
+// ResponseError is returned for responses with a non-2xx status code.
+type ResponseError struct {
+	StatusCode int
+	Status     string
+	Message    string
+}
+
+func (e *ResponseError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("error response: %s", e.Status)
+	}
+	return fmt.Sprintf("error response: %s: %s", e.Status, e.Message)
+}
+
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+func newResponseError(resp *http.Response) *ResponseError {
+	bodyBytes, _ := io.ReadAll(resp.Body)
+
+	message := strings.TrimSpace(string(bodyBytes))
+	var body errorResponse
+	if err := json.Unmarshal(bodyBytes, &body); err == nil && body.Message != "" {
+		message = body.Message
+	} else if strings.HasPrefix(message, "<") {
+		// Azure DevOps answers some failures, e.g. expired tokens, with an
+		// HTML page that is of no use in a terminal.
+		message = ""
+	}
+
+	return &ResponseError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Message:    message,
+	}
+}
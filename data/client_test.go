@@ -0,0 +1,137 @@
+package data
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestClientSendsRequestsToBaseURL(t *testing.T) {
+	type request struct {
+		path          string
+		apiVersion    string
+		authorization string
+	}
+	var requests []request
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, request{
+			path:          r.URL.Path,
+			apiVersion:    r.URL.Query().Get("api-version"),
+			authorization: r.Header.Get("Authorization"),
+		})
+
+		switch r.URL.Path {
+		case "/collection/_apis/connectionData":
+			fmt.Fprint(w, `{"authenticatedUser": {"id": "user-id", "providerDisplayName": "Jane Doe",
+				"properties": {"Account": {"$value": "jane@example.com"}}}}`)
+		case "/collection/project/_apis/git/repositories/repo/pullrequests":
+			fmt.Fprint(w, `{"value": [{"pullRequestId": 1, "title": "Fix"}], "count": 1}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/", "collection", "6.0", PersonalAccessTokenAuth{Token: "token"})
+	client.HTTPClient = server.Client()
+
+	user, err := client.FetchCurrentUser(context.Background())
+	if err != nil {
+		t.Fatalf("FetchCurrentUser() error = %v", err)
+	}
+	want := User{ID: "user-id", DisplayName: "Jane Doe", UniqueName: "jane@example.com"}
+	if !reflect.DeepEqual(user, want) {
+		t.Errorf("FetchCurrentUser() = %+v, want %+v", user, want)
+	}
+
+	response, hasMore, err := client.FetchPullRequestsByProject(context.Background(), FetchPRRequest{
+		ProjectID: "project",
+		RepoID:    "repo",
+	})
+	if err != nil {
+		t.Fatalf("FetchPullRequestsByProject() error = %v", err)
+	}
+	if hasMore || len(response.Value) != 1 || response.Value[0].PullRequestID != 1 {
+		t.Errorf("FetchPullRequestsByProject() = %+v, %v", response, hasMore)
+	}
+
+	authorization := "Basic " + base64.StdEncoding.EncodeToString([]byte("any:token"))
+	wantRequests := []request{
+		{"/collection/_apis/connectionData", "6.0-preview.1", authorization},
+		{"/collection/project/_apis/git/repositories/repo/pullrequests", "6.0", authorization},
+	}
+	if len(requests) != len(wantRequests) {
+		t.Fatalf("requests = %+v, want %+v", requests, wantRequests)
+	}
+	for i := range wantRequests {
+		if requests[i] != wantRequests[i] {
+			t.Errorf("request %d = %+v, want %+v", i, requests[i], wantRequests[i])
+		}
+	}
+}
+
+func TestClientReportsErrorResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"message": "The requested REST API version of 7.1 is out of range."}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "collection", "", nil)
+	client.HTTPClient = server.Client()
+
+	_, err := client.FetchCurrentUser(context.Background())
+	var responseErr *ResponseError
+	if !errors.As(err, &responseErr) {
+		t.Fatalf("FetchCurrentUser() error = %v, want a *ResponseError", err)
+	}
+	if responseErr.StatusCode != http.StatusBadRequest ||
+		responseErr.Message != "The requested REST API version of 7.1 is out of range." {
+		t.Errorf("FetchCurrentUser() error = %+v", responseErr)
+	}
+}
+
+func TestNewClientDefaults(t *testing.T) {
+	client := NewClient("", "org", "", nil)
+	if client.BaseURL != DefaultBaseURL || client.APIVersion != DefaultAPIVersion {
+		t.Errorf("NewClient() = %q, %q, want %q, %q", client.BaseURL, client.APIVersion, DefaultBaseURL, DefaultAPIVersion)
+	}
+}
+
+func TestClientSendsContentTypes(t *testing.T) {
+	contentTypes := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentTypes[r.Method+" "+r.URL.Path] = r.Header.Get("Content-Type")
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "org", "", nil)
+	client.HTTPClient = server.Client()
+	ctx := context.Background()
+
+	if _, err := client.SetWorkItemState(ctx, WorkItem{ID: 1}, "Done"); err != nil {
+		t.Fatalf("SetWorkItemState() error = %v", err)
+	}
+	if _, err := client.SearchIdentities(ctx, "jane"); err != nil {
+		t.Fatalf("SearchIdentities() error = %v", err)
+	}
+	if _, err := client.FetchCurrentUser(ctx); err == nil {
+		t.Fatal("FetchCurrentUser() of an empty response succeeded")
+	}
+
+	want := map[string]string{
+		"PATCH /org/_apis/wit/workitems/1":          "application/json-patch+json",
+		"POST /org/_apis/IdentityPicker/Identities": "application/json",
+		"GET /org/_apis/connectionData":             "",
+	}
+	if !reflect.DeepEqual(contentTypes, want) {
+		t.Errorf("content types = %v, want %v", contentTypes, want)
+	}
+}
@@ -0,0 +1,84 @@
+package data
+
+import (
+	"context"
+	"net/url"
+)
+
+// identityPickerMaxResults caps the identities returned by a search.
+const identityPickerMaxResults = 10
+
+type identityPickerRequest struct {
+	Query           string                `json:"query"`
+	IdentityTypes   []string              `json:"identityTypes"`
+	OperationScopes []string              `json:"operationScopes"`
+	Properties      []string              `json:"properties"`
+	Options         identityPickerOptions `json:"options"`
+}
+
+type identityPickerOptions struct {
+	MinResults int `json:"MinResults"`
+	MaxResults int `json:"MaxResults"`
+}
+
+type IdentityPickerResponse struct {
+	Results []IdentityPickerResultResponse `json:"results"`
+}
+
+type IdentityPickerResultResponse struct {
+	Identities []PickerIdentityResponse `json:"identities"`
+}
+
+type PickerIdentityResponse struct {
+	LocalID       string `json:"localId"`
+	DisplayName   string `json:"displayName"`
+	SignInAddress string `json:"signInAddress"`
+	Mail          string `json:"mail"`
+}
+
+// SearchIdentities finds the users and groups of the organization whose name
+// or mail address starts with query.
+func (c *Client) SearchIdentities(ctx context.Context, query string) ([]User, error) {
+	apiQuery := url.Values{}
+	apiQuery.Set("api-version", c.previewVersion())
+
+	body := identityPickerRequest{
+		Query:           query,
+		IdentityTypes:   []string{"user", "group"},
+		OperationScopes: []string{"ims", "source"},
+		Properties:      []string{"DisplayName", "Mail", "SignInAddress"},
+		Options: identityPickerOptions{
+			MinResults: 1,
+			MaxResults: identityPickerMaxResults,
+		},
+	}
+
+	var response IdentityPickerResponse
+	err := c.do(ctx, "POST", c.url("_apis/IdentityPicker/Identities", apiQuery), body, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []User
+	for _, result := range response.Results {
+		for _, identity := range result.Identities {
+			// Identities that never signed in to the organization have no
+			// local id and cannot review pull requests.
+			if identity.LocalID == "" {
+				continue
+			}
+
+			uniqueName := identity.SignInAddress
+			if uniqueName == "" {
+				uniqueName = identity.Mail
+			}
+			users = append(users, User{
+				ID:          identity.LocalID,
+				DisplayName: identity.DisplayName,
+				UniqueName:  uniqueName,
+			})
+		}
+	}
+
+	return users, nil
+}
@@ -0,0 +1,189 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ChangeTypeAdd    = "add"
+	ChangeTypeEdit   = "edit"
+	ChangeTypeDelete = "delete"
+	ChangeTypeRename = "rename"
+)
+
+// changesPageSize is the number of changes requested per page.
+const changesPageSize = 1000
+
+// Iteration is a push to the source branch of a pull request.
+type Iteration struct {
+	ID           int
+	Description  string
+	Author       string
+	CreatedDate  time.Time
+	SourceCommit string
+	TargetCommit string
+}
+
+// FileChange is a file changed by a pull request. The content of the file
+// before and after the change are the blobs OriginalObjectID and ObjectID,
+// either is empty if the file was added or deleted.
+type FileChange struct {
+	Path             string
+	OriginalPath     string
+	ChangeType       string
+	ObjectID         string
+	OriginalObjectID string
+}
+
+type IterationsResponse struct {
+	Value []IterationResponse `json:"value"`
+}
+
+type IterationResponse struct {
+	ID              int            `json:"id"`
+	Description     string         `json:"description"`
+	Author          UserResponse   `json:"author"`
+	CreatedDate     time.Time      `json:"createdDate"`
+	SourceRefCommit CommitResponse `json:"sourceRefCommit"`
+	TargetRefCommit CommitResponse `json:"targetRefCommit"`
+}
+
+type IterationChangesResponse struct {
+	ChangeEntries []ChangeEntryResponse `json:"changeEntries"`
+	NextSkip      int                   `json:"nextSkip"`
+	NextTop       int                   `json:"nextTop"`
+}
+
+type ChangeEntryResponse struct {
+	ChangeTrackingID int          `json:"changeTrackingId"`
+	ChangeType       string       `json:"changeType"`
+	OriginalPath     string       `json:"originalPath"`
+	Item             ItemResponse `json:"item"`
+}
+
+type ItemResponse struct {
+	Path             string `json:"path"`
+	ObjectID         string `json:"objectId"`
+	OriginalObjectID string `json:"originalObjectId"`
+	GitObjectType    string `json:"gitObjectType"`
+	IsFolder         bool   `json:"isFolder"`
+}
+
+// LatestIteration returns the id of the latest iteration of a pull request,
+// or 0 if its iterations are unknown.
+func (pr PullRequestData) LatestIteration() int {
+	if len(pr.Iterations) == 0 {
+		return 0
+	}
+	return pr.Iterations[len(pr.Iterations)-1].ID
+}
+
+// FetchIterations fetches the iterations of a pull request, oldest first.
+func (c *Client) FetchIterations(ctx context.Context, pr PullRequestData) ([]Iteration, error) {
+	var response IterationsResponse
+	err := c.do(ctx, "GET", c.url(pullRequestPath(pr)+"/iterations", nil), nil, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	iterations := make([]Iteration, 0, len(response.Value))
+	for _, iteration := range response.Value {
+		iterations = append(iterations, Iteration{
+			ID:           iteration.ID,
+			Description:  iteration.Description,
+			Author:       iteration.Author.DisplayName,
+			CreatedDate:  iteration.CreatedDate,
+			SourceCommit: iteration.SourceRefCommit.CommitID,
+			TargetCommit: iteration.TargetRefCommit.CommitID,
+		})
+	}
+
+	return iterations, nil
+}
+
+// FetchIterationChanges fetches the files changed by a pull request up to
+// iteration since iteration compareTo, or since its target branch if
+// compareTo is 0.
+func (c *Client) FetchIterationChanges(ctx context.Context, pr PullRequestData, iteration int, compareTo int) ([]FileChange, error) {
+	path := fmt.Sprintf("%s/iterations/%d/changes", pullRequestPath(pr), iteration)
+
+	var changes []FileChange
+	skip := 0
+	for {
+		query := url.Values{}
+		query.Set("$compareTo", strconv.Itoa(compareTo))
+		query.Set("$top", strconv.Itoa(changesPageSize))
+		query.Set("$skip", strconv.Itoa(skip))
+
+		var response IterationChangesResponse
+		err := c.do(ctx, "GET", c.url(path, query), nil, &response)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range response.ChangeEntries {
+			if entry.Item.IsFolder || entry.Item.GitObjectType == "tree" {
+				continue
+			}
+			changes = append(changes, newFileChange(entry))
+		}
+
+		if response.NextTop == 0 || response.NextSkip <= skip {
+			return changes, nil
+		}
+		skip = response.NextSkip
+	}
+}
+
+func newFileChange(entry ChangeEntryResponse) FileChange {
+	change := FileChange{
+		Path:             entry.Item.Path,
+		OriginalPath:     entry.OriginalPath,
+		ChangeType:       entry.ChangeType,
+		ObjectID:         entry.Item.ObjectID,
+		OriginalObjectID: entry.Item.OriginalObjectID,
+	}
+
+	// Added files have no original content and deleted files none left. The
+	// change type lists all kinds of a change, e.g. "edit, rename".
+	switch {
+	case strings.Contains(change.ChangeType, ChangeTypeDelete):
+		if change.OriginalObjectID == "" {
+			change.OriginalObjectID = change.ObjectID
+		}
+		change.ObjectID = ""
+	case strings.Contains(change.ChangeType, ChangeTypeAdd):
+		change.OriginalObjectID = ""
+	}
+	if change.OriginalPath == "" {
+		change.OriginalPath = change.Path
+	}
+
+	return change
+}
+
+// FetchBlob fetches the content of a file of the repository of a pull
+// request by its object id. An empty object id has no content.
+func (c *Client) FetchBlob(ctx context.Context, pr PullRequestData, objectID string) ([]byte, error) {
+	if objectID == "" {
+		return nil, nil
+	}
+
+	path := fmt.Sprintf(
+		"%s/_apis/git/repositories/%s/blobs/%s",
+		url.PathEscape(pr.ProjectID),
+		url.PathEscape(pr.RepositoryID),
+		url.PathEscape(objectID),
+	)
+	query := url.Values{}
+	query.Set("$format", "octetStream")
+
+	var content []byte
+	err := c.do(ctx, "GET", c.url(path, query), nil, &content)
+	return content, err
+}
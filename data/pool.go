@@ -0,0 +1,47 @@
+package data
+
+import (
+	"context"
+	"sync"
+)
+
+const DefaultConcurrency = 8
+
+// forEachConcurrently calls fn for every index in [0, n) using at most
+// concurrency goroutines. No new calls are started once ctx is done.
+func forEachConcurrently(
+	ctx context.Context,
+	n int,
+	concurrency int,
+	fn func(ctx context.Context, i int),
+) {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	if concurrency > n {
+		concurrency = n
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(ctx, i)
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
@@ -1,37 +1,140 @@
 package data
 
 import (
-	"encoding/base64"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const DefaultPageSize = 100
+
+const (
+	MergeStatusNotSet           = "notSet"
+	MergeStatusQueued           = "queued"
+	MergeStatusSucceeded        = "succeeded"
+	MergeStatusConflicts        = "conflicts"
+	MergeStatusRejectedByPolicy = "rejectedByPolicy"
+	MergeStatusFailure          = "failure"
 )
 
 type PullRequestData struct {
-	ID                 int
-	Title              string
-	Status             string
-	MergeStatus        string
-	SourceBranch       string
-	CreatedBy          string
-	IsDraft            bool
-	RepositoryName     string
-	RepositoryID       string
-	IsRequiredReviewer bool
-	Vote               int
+	ID                    int
+	Title                 string
+	Status                string
+	MergeStatus           string
+	SourceBranch          string
+	CreatedBy             string
+	IsDraft               bool
+	RepositoryName        string
+	RepositoryID          string
+	ProjectID             string
+	ProjectName           string
+	IsRequiredReviewer    bool
+	Vote                  int
+	TargetBranch          string
+	Description           string
+	CreationDate          time.Time
+	Labels                []string
+	Reviewers             []Reviewer
+	LastMergeSourceCommit string
+	// AutoCompleteSetBy is the display name of the user who set the pull
+	// request to complete automatically, or empty if it is not set.
+	AutoCompleteSetBy string
+	CompletionOptions CompletionOptions
+	// UnresolvedThreads is the number of active threads written by users,
+	// or -1 if the threads are not known.
+	UnresolvedThreads int
+	Checks            []Check
+	// ChecksState sums up Checks, or is CheckStateUnknown if they could not
+	// be fetched.
+	ChecksState string
+	// Conflicts lists the paths of the files that conflict when MergeStatus
+	// is MergeStatusConflicts.
+	Conflicts []string
+	// Iterations are the pushes to the source branch, oldest first, or nil
+	// if they could not be fetched.
+	Iterations []Iteration
+	// WorkItems are the linked work items, or nil if they could not be
+	// fetched.
+	WorkItems []WorkItem
+	// HasIndicators tells whether UnresolvedThreads, Checks and WorkItems
+	// were fetched. The list of pull requests leaves them out.
+	HasIndicators bool
+	// HasDetails tells whether Conflicts and Iterations were fetched. Only
+	// FetchPullRequest fetches them.
+	HasDetails bool
+}
+
+// PullRequestIndicators are what the columns show of a pull request beyond
+// the list of pull requests: its unresolved threads, checks and work items.
+type PullRequestIndicators struct {
+	UnresolvedThreads int
+	Checks            []Check
+	ChecksState       string
+	WorkItems         []WorkItem
+}
+
+type Reviewer struct {
+	ID          string
+	DisplayName string
+	UniqueName  string
+	Vote        int
+	IsRequired  bool
+	IsContainer bool
 }
 
+// FetchPRRequest selects the pull requests of a repository matching
+// SearchCriteria. Pages of PageSize pull requests are requested starting at
+// Skip until the repository is exhausted or Limit pull requests were fetched.
+// A Limit of 0 fetches all.
 type FetchPRRequest struct {
-	OrgName             string
-	ProjectID           string
-	RepoID              string
-	PersonalAccessToken string
+	ProjectID      string
+	RepoID         string
+	SearchCriteria SearchCriteria
+	Skip           int
+	PageSize       int
+	Limit          int
 }
 
 type PullRequests struct {
 	Prs        []PullRequestData
 	TotalCount int
+	Errors     []RepositoryError
+	// NextPageRequests continue the requests of repositories that have more
+	// pull requests than their Limit allowed to fetch.
+	NextPageRequests []FetchPRRequest
+}
+
+// RepositoryError records why the pull requests of a repository could not
+// be fetched.
+type RepositoryError struct {
+	ProjectID string
+	RepoID    string
+	Err       error
+}
+
+func (e RepositoryError) Error() string {
+	return fmt.Sprintf("fetching pull requests of %s/%s: %v", e.ProjectID, e.RepoID, e.Err)
+}
+
+func (e RepositoryError) Unwrap() error {
+	return e.Err
+}
+
+// Reason describes the failure with the HTTP status if there was a response.
+func (e RepositoryError) Reason() string {
+	var responseErr *ResponseError
+	if errors.As(e.Err, &responseErr) {
+		if responseErr.Message == "" {
+			return responseErr.Status
+		}
+		return fmt.Sprintf("%s: %s", responseErr.Status, responseErr.Message)
+	}
+	return e.Err.Error()
 }
 
 type FetchPRResponse struct {
@@ -40,123 +143,371 @@ type FetchPRResponse struct {
 }
 
 type PullRequestResponse struct {
-	Repository    RepositoryResponse `json:"repository"`
-	PullRequestID int                `json:"pullRequestId"`
-	Title         string             `json:"title"`
-	Status        string             `json:"status"`
-	IsDraft       bool               `json:"isDraft"`
-	CreatedBy     UserResponse       `json:"createdBy"`
-	Reviewers     []ReviewerResponse `json:"reviewers"`
-	SourceRefName string             `json:"sourceRefName"`
-	TargetRefName string             `json:"targetRefName"`
-	MergeStatus   string             `json:"mergeStatus"`
+	Repository            RepositoryResponse `json:"repository"`
+	PullRequestID         int                `json:"pullRequestId"`
+	Title                 string             `json:"title"`
+	Status                string             `json:"status"`
+	IsDraft               bool               `json:"isDraft"`
+	CreatedBy             UserResponse       `json:"createdBy"`
+	Reviewers             []ReviewerResponse `json:"reviewers"`
+	SourceRefName         string             `json:"sourceRefName"`
+	TargetRefName         string             `json:"targetRefName"`
+	MergeStatus           string             `json:"mergeStatus"`
+	Description           string             `json:"description"`
+	CreationDate          time.Time          `json:"creationDate"`
+	Labels                []LabelResponse    `json:"labels"`
+	LastMergeSourceCommit CommitResponse     `json:"lastMergeSourceCommit"`
+	MergeFailureMessage   string             `json:"mergeFailureMessage"`
+	AutoCompleteSetBy     *UserResponse      `json:"autoCompleteSetBy"`
+	CompletionOptions     CompletionOptions  `json:"completionOptions"`
+}
+
+type CommitResponse struct {
+	CommitID string `json:"commitId"`
+}
+
+type LabelResponse struct {
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
 }
 
 type RepositoryResponse struct {
+	ID      string          `json:"id"`
+	Name    string          `json:"name"`
+	Project ProjectResponse `json:"project"`
+}
+
+type ProjectResponse struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
 }
 
 type UserResponse struct {
+	ID          string `json:"id"`
 	DisplayName string `json:"displayName"`
 	UniqueName  string `json:"uniqueName"`
 }
 
 type ReviewerResponse struct {
-	DisplayName string `json:"displayName"`
-	UniqueName  string `json:"uniqueName"`
-	Vote        int    `json:"vote"`
-	HasDeclined bool   `json:"hasDeclined"`
-	IsRequired  bool   `json:"isRequired"`
+	ID          string             `json:"id"`
+	DisplayName string             `json:"displayName"`
+	UniqueName  string             `json:"uniqueName"`
+	Vote        int                `json:"vote"`
+	HasDeclined bool               `json:"hasDeclined"`
+	IsRequired  bool               `json:"isRequired"`
+	IsContainer bool               `json:"isContainer"`
+	VotedFor    []ReviewerResponse `json:"votedFor"`
 }
 
-func FetchPullRequests(configs []FetchPRRequest) (PullRequests, error) {
-	prs := make([]PullRequestData, 0)
+// FetchPullRequests fetches the pull requests of all repositories with at
+// most concurrency requests in flight. The result keeps the order of configs.
+// Repositories that fail are reported in PullRequests.Errors, an error is
+// only returned when ctx is done. The pull requests come without their
+// indicators and details, see FetchPullRequestIndicators and
+// FetchPullRequest.
+func (c *Client) FetchPullRequests(
+	ctx context.Context,
+	configs []FetchPRRequest,
+	user User,
+	concurrency int,
+) (PullRequests, error) {
+	responses := make([]*FetchPRResponse, len(configs))
+	hasMore := make([]bool, len(configs))
+	errs := make([]error, len(configs))
 
-	for _, config := range configs {
-		response, err := FetchPullRequestsByProject(config)
-		if err != nil {
-			return PullRequests{}, fmt.Errorf("fetching pull request: %w", err)
+	forEachConcurrently(ctx, len(configs), concurrency, func(ctx context.Context, i int) {
+		responses[i], hasMore[i], errs[i] = c.FetchPullRequestsByProject(ctx, configs[i])
+	})
+
+	if err := ctx.Err(); err != nil {
+		return PullRequests{}, err
+	}
+
+	prs := make([]PullRequestData, 0)
+	repoErrs := make([]RepositoryError, 0)
+	nextPageRequests := make([]FetchPRRequest, 0)
+	for i, response := range responses {
+		if errs[i] != nil {
+			repoErrs = append(repoErrs, RepositoryError{
+				ProjectID: configs[i].ProjectID,
+				RepoID:    configs[i].RepoID,
+				Err:       errs[i],
+			})
+			continue
 		}
 
 		if response != nil {
-			projectPrs := getPullRequestData(response)
+			projectPrs := getPullRequestData(response, user)
 			prs = append(prs, projectPrs...)
 		}
+
+		if hasMore[i] {
+			nextPageRequest := configs[i]
+			nextPageRequest.Skip += len(response.Value)
+			nextPageRequests = append(nextPageRequests, nextPageRequest)
+		}
+	}
+
+	return PullRequests{prs, len(prs), repoErrs, nextPageRequests}, nil
+}
+
+// FetchPullRequestIndicators fetches the unresolved threads, the checks and
+// the work items of a pull request. Indicators that fail to load are marked
+// as unknown.
+func (c *Client) FetchPullRequestIndicators(ctx context.Context, pr PullRequestData) PullRequestIndicators {
+	var indicators PullRequestIndicators
+
+	count, err := c.CountUnresolvedThreads(ctx, pr)
+	if err != nil {
+		count = -1
+	}
+	indicators.UnresolvedThreads = count
+
+	checks, err := c.FetchChecks(ctx, pr)
+	if err != nil {
+		indicators.ChecksState = CheckStateUnknown
+	} else {
+		indicators.Checks = checks
+		indicators.ChecksState = ChecksState(checks)
+	}
+
+	indicators.WorkItems, _ = c.FetchPullRequestWorkItems(ctx, pr)
+
+	return indicators
+}
+
+// SetIndicators fills in the indicators of the pull request.
+func (pr *PullRequestData) SetIndicators(indicators PullRequestIndicators) {
+	pr.UnresolvedThreads = indicators.UnresolvedThreads
+	pr.Checks = indicators.Checks
+	pr.ChecksState = indicators.ChecksState
+	pr.WorkItems = indicators.WorkItems
+	pr.HasIndicators = true
+}
+
+type ConflictsResponse struct {
+	Value []ConflictResponse `json:"value"`
+}
+
+type ConflictResponse struct {
+	ConflictID   int    `json:"conflictId"`
+	ConflictPath string `json:"conflictPath"`
+	ConflictType string `json:"conflictType"`
+}
+
+// FetchConflicts fetches the paths of the files of a pull request that
+// conflict with its target branch.
+func (c *Client) FetchConflicts(ctx context.Context, pr PullRequestData) ([]string, error) {
+	var response ConflictsResponse
+	err := c.do(ctx, "GET", c.url(pullRequestPath(pr)+"/conflicts", nil), nil, &response)
+	if err != nil {
+		return nil, err
 	}
 
-	return PullRequests{prs, len(prs)}, nil
+	paths := make([]string, 0, len(response.Value))
+	for _, conflict := range response.Value {
+		paths = append(paths, conflict.ConflictPath)
+	}
+	return paths, nil
 }
 
-func getPullRequestData(response *FetchPRResponse) []PullRequestData {
+func getPullRequestData(response *FetchPRResponse, user User) []PullRequestData {
 	result := make([]PullRequestData, 0)
 
 	for _, prResponse := range response.Value {
+		result = append(result, newPullRequestData(prResponse, user))
+	}
 
-		var userReviewer *ReviewerResponse = nil
-		for _, reviewer := range prResponse.Reviewers {
-			if reviewer.DisplayName == "Tobias Landenberger" {
-				userReviewer = &reviewer
-				break
-			}
-		}
+	return result
+}
+
+func newPullRequestData(prResponse PullRequestResponse, user User) PullRequestData {
+	vote, isRequiredReviewer := getUserReview(prResponse.Reviewers, user)
 
-		vote := 0
-		isRequiredReviewer := false
-		if userReviewer != nil {
-			vote = userReviewer.Vote
-			isRequiredReviewer = userReviewer.IsRequired
+	labels := make([]string, 0, len(prResponse.Labels))
+	for _, label := range prResponse.Labels {
+		if label.Active {
+			labels = append(labels, label.Name)
 		}
+	}
 
-		result = append(result, PullRequestData{
-			ID:                 prResponse.PullRequestID,
-			Title:              prResponse.Title,
-			CreatedBy:          prResponse.CreatedBy.DisplayName,
-			Status:             prResponse.Status,
-			MergeStatus:        prResponse.MergeStatus,
-			IsDraft:            prResponse.IsDraft,
-			RepositoryID:       prResponse.Repository.ID,
-			RepositoryName:     prResponse.Repository.Name,
-			IsRequiredReviewer: isRequiredReviewer,
-			SourceBranch:       prResponse.SourceRefName,
-			Vote:               vote,
+	reviewers := make([]Reviewer, 0, len(prResponse.Reviewers))
+	for _, reviewer := range prResponse.Reviewers {
+		reviewers = append(reviewers, Reviewer{
+			ID:          reviewer.ID,
+			DisplayName: reviewer.DisplayName,
+			UniqueName:  reviewer.UniqueName,
+			Vote:        reviewer.Vote,
+			IsRequired:  reviewer.IsRequired,
+			IsContainer: reviewer.IsContainer,
 		})
 	}
 
-	return result
+	autoCompleteSetBy := ""
+	if prResponse.AutoCompleteSetBy != nil {
+		autoCompleteSetBy = prResponse.AutoCompleteSetBy.DisplayName
+	}
+
+	return PullRequestData{
+		ID:                    prResponse.PullRequestID,
+		Title:                 prResponse.Title,
+		CreatedBy:             prResponse.CreatedBy.DisplayName,
+		Status:                prResponse.Status,
+		MergeStatus:           prResponse.MergeStatus,
+		IsDraft:               prResponse.IsDraft,
+		RepositoryID:          prResponse.Repository.ID,
+		RepositoryName:        prResponse.Repository.Name,
+		ProjectID:             prResponse.Repository.Project.ID,
+		ProjectName:           prResponse.Repository.Project.Name,
+		IsRequiredReviewer:    isRequiredReviewer,
+		SourceBranch:          prResponse.SourceRefName,
+		Vote:                  vote,
+		TargetBranch:          prResponse.TargetRefName,
+		Description:           prResponse.Description,
+		CreationDate:          prResponse.CreationDate,
+		Labels:                labels,
+		Reviewers:             reviewers,
+		LastMergeSourceCommit: prResponse.LastMergeSourceCommit.CommitID,
+		AutoCompleteSetBy:     autoCompleteSetBy,
+		CompletionOptions:     prResponse.CompletionOptions,
+		UnresolvedThreads:     -1,
+	}
 }
 
-func FetchPullRequestsByProject(config FetchPRRequest) (*FetchPRResponse, error) {
-	url := fmt.Sprintf("https://dev.azure.com/%s/%s/_apis/git/repositories/%s/pullrequests?api-version=6.0", config.OrgName, config.ProjectID, config.RepoID)
+// FetchPullRequest fetches a single pull request with its indicators and
+// details. Unlike the list of pull requests, it carries the full
+// description.
+func (c *Client) FetchPullRequest(
+	ctx context.Context,
+	projectID string,
+	repoID string,
+	id int,
+	user User,
+) (PullRequestData, error) {
+	path := fmt.Sprintf(
+		"%s/_apis/git/repositories/%s/pullrequests/%d",
+		url.PathEscape(projectID),
+		url.PathEscape(repoID),
+		id,
+	)
 
-	req, err := http.NewRequest("GET", url, nil)
+	var response PullRequestResponse
+	err := c.do(ctx, "GET", c.url(path, nil), nil, &response)
 	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
+		return PullRequestData{}, err
 	}
 
-	// Add the personal access token for authentication
-	auth := "any:" + config.PersonalAccessToken
-	encodedAuth := base64.StdEncoding.EncodeToString([]byte(auth))
-	req.Header.Set("Authorization", "Basic "+encodedAuth)
+	pr := newPullRequestData(response, user)
+	pr.SetIndicators(c.FetchPullRequestIndicators(ctx, pr))
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("making request: %w", err)
+	if pr.MergeStatus == MergeStatusConflicts {
+		// Without the paths the merge status alone still tells about the
+		// conflicts.
+		pr.Conflicts, _ = c.FetchConflicts(ctx, pr)
+	}
+	pr.Iterations, _ = c.FetchIterations(ctx, pr)
+	pr.HasDetails = true
+
+	return pr, nil
+}
+
+// PullRequestWebURL returns the address of the pull request in the web portal.
+func (c *Client) PullRequestWebURL(pr PullRequestData) string {
+	return c.webURL(
+		pr.ProjectName,
+		"_git",
+		pr.RepositoryName,
+		"pullrequest",
+		strconv.Itoa(pr.ID),
+	)
+}
+
+// RepositoryRemoteURL returns the HTTPS address the repository of the pull
+// request is cloned from.
+func (c *Client) RepositoryRemoteURL(pr PullRequestData) string {
+	return c.webURL(pr.ProjectName, "_git", pr.RepositoryName)
+}
+
+// getUserReview returns the user's vote and whether the user is required to
+// review. The user is required to review if the user or one of the user's
+// groups and teams is a required reviewer. The reviewer entry of the user
+// also lists the groups the user voted for, so they count even if the
+// groups of the user are not known.
+func getUserReview(reviewers []ReviewerResponse, user User) (int, bool) {
+	requiredContainers := map[string]bool{}
+	isRequired := false
+	for _, reviewer := range reviewers {
+		if reviewer.IsContainer && reviewer.IsRequired {
+			requiredContainers[reviewer.ID] = true
+			isRequired = isRequired || slices.Contains(user.Groups, reviewer.ID)
+		}
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		bodyString := string(bodyBytes)
-		return nil, fmt.Errorf("error response: %s", bodyString)
+	for _, reviewer := range reviewers {
+		if reviewer.ID != user.ID {
+			continue
+		}
+
+		isRequired = isRequired || reviewer.IsRequired
+		for _, votedFor := range reviewer.VotedFor {
+			isRequired = isRequired || requiredContainers[votedFor.ID]
+		}
+		return reviewer.Vote, isRequired
 	}
 
-	var response FetchPRResponse
-	err = json.NewDecoder(resp.Body).Decode(&response)
-	if err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
+	return 0, isRequired
+}
+
+// FetchPullRequestsByProject follows $top/$skip through the pull requests of
+// a repository. It reports whether the repository has more pull requests than
+// the request's Limit allowed to fetch.
+func (c *Client) FetchPullRequestsByProject(
+	ctx context.Context,
+	config FetchPRRequest,
+) (*FetchPRResponse, bool, error) {
+	path := fmt.Sprintf(
+		"%s/_apis/git/repositories/%s/pullrequests",
+		url.PathEscape(config.ProjectID),
+		url.PathEscape(config.RepoID),
+	)
+
+	pageSize := config.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
 	}
 
-	return &response, nil
+	result := FetchPRResponse{Value: make([]PullRequestResponse, 0)}
+	skip := config.Skip
+	for {
+		top := pageSize
+		if config.Limit > 0 {
+			// Asking for one pull request beyond the limit tells whether
+			// the repository has more, without loading an empty page later.
+			top = min(top, config.Limit-len(result.Value)+1)
+		}
+
+		query := url.Values{}
+		config.SearchCriteria.setQuery(query)
+		query.Set("$top", strconv.Itoa(top))
+		query.Set("$skip", strconv.Itoa(skip))
+
+		var response FetchPRResponse
+		err := c.do(ctx, "GET", c.url(path, query), nil, &response)
+		if err != nil {
+			return nil, false, err
+		}
+
+		page := response.Value
+		hasMore := config.Limit > 0 && len(result.Value)+len(page) > config.Limit
+		if hasMore {
+			page = page[:config.Limit-len(result.Value)]
+		}
+		result.Value = append(result.Value, page...)
+		result.Count = len(result.Value)
+		skip += len(page)
+
+		if hasMore || len(response.Value) < top {
+			return &result, hasMore, nil
+		}
+	}
 }
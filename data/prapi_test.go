@@ -0,0 +1,394 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// pullRequestServer serves the pull requests of repositories, following
+// $top and $skip like Azure DevOps does.
+type pullRequestServer struct {
+	// counts are the number of pull requests of every repository, the ids
+	// of the pull requests of a repository start at 1.
+	counts map[string]int
+	// failing repositories answer with a 404.
+	failing map[string]bool
+	// delays hold back the answers of repositories.
+	delays map[string]time.Duration
+	// block holds back all answers until the request is cancelled.
+	block bool
+
+	mu      sync.Mutex
+	queries []url.Values
+}
+
+func (s *pullRequestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.queries = append(s.queries, r.URL.Query())
+	s.mu.Unlock()
+
+	// /org/project/_apis/git/repositories/repo/pullrequests
+	parts := strings.Split(r.URL.Path, "/")
+	if len(parts) != 8 || parts[7] != "pullrequests" {
+		http.NotFound(w, r)
+		return
+	}
+	repo := parts[6]
+
+	if s.block {
+		<-r.Context().Done()
+		return
+	}
+	time.Sleep(s.delays[repo])
+	if s.failing[repo] {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, `{"message": "The repository %s does not exist."}`, repo)
+		return
+	}
+
+	top, _ := strconv.Atoi(r.URL.Query().Get("$top"))
+	skip, _ := strconv.Atoi(r.URL.Query().Get("$skip"))
+	var values []string
+	for id := skip + 1; id <= min(skip+top, s.counts[repo]); id++ {
+		values = append(values, fmt.Sprintf(
+			`{"pullRequestId": %d, "title": "%s #%d", "repository": {"id": %q}}`,
+			id, repo, id, repo,
+		))
+	}
+	fmt.Fprintf(w, `{"value": [%s], "count": %d}`, strings.Join(values, ","), len(values))
+}
+
+func newPullRequestClient(t *testing.T, server *pullRequestServer) *Client {
+	t.Helper()
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	client := NewClient(httpServer.URL, "org", "", nil)
+	client.HTTPClient = httpServer.Client()
+	return client
+}
+
+func prTitles(prs []PullRequestData) []string {
+	titles := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		titles = append(titles, pr.Title)
+	}
+	return titles
+}
+
+func TestFetchPullRequestsKeepsTheOrderOfRequests(t *testing.T) {
+	repos := []string{"a", "b", "c", "d", "e"}
+	server := &pullRequestServer{counts: map[string]int{}, delays: map[string]time.Duration{}}
+	var requests []FetchPRRequest
+	var want []string
+	for i, repo := range repos {
+		server.counts[repo] = 2
+		// The first repositories answer last.
+		server.delays[repo] = time.Duration(len(repos)-i) * 10 * time.Millisecond
+		requests = append(requests, FetchPRRequest{ProjectID: "project", RepoID: repo})
+		want = append(want, repo+" #1", repo+" #2")
+	}
+	client := newPullRequestClient(t, server)
+
+	for _, concurrency := range []int{1, 2, len(repos)} {
+		result, err := client.FetchPullRequests(context.Background(), requests, User{}, concurrency)
+		if err != nil {
+			t.Fatalf("FetchPullRequests() error = %v", err)
+		}
+		if got := prTitles(result.Prs); strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("FetchPullRequests() with concurrency %d = %q, want %q", concurrency, got, want)
+		}
+		if result.TotalCount != len(want) {
+			t.Errorf("TotalCount = %d, want %d", result.TotalCount, len(want))
+		}
+	}
+}
+
+func TestFetchPullRequestsStopsWhenCancelled(t *testing.T) {
+	server := &pullRequestServer{block: true}
+	client := newPullRequestClient(t, server)
+	requests := []FetchPRRequest{
+		{ProjectID: "project", RepoID: "a"},
+		{ProjectID: "project", RepoID: "b"},
+		{ProjectID: "project", RepoID: "c"},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	done := make(chan error)
+	go func() {
+		_, err := client.FetchPullRequests(ctx, requests, User{}, 1)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("FetchPullRequests() error = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("FetchPullRequests() did not return after it was cancelled")
+	}
+
+	// The requests not started before the cancellation are never sent.
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if len(server.queries) != 1 {
+		t.Errorf("server got %d requests, want 1", len(server.queries))
+	}
+}
+
+func TestFetchPullRequestsReportsFailingRepositories(t *testing.T) {
+	server := &pullRequestServer{
+		counts:  map[string]int{"a": 1, "b": 1, "c": 1},
+		failing: map[string]bool{"b": true},
+	}
+	client := newPullRequestClient(t, server)
+	requests := []FetchPRRequest{
+		{ProjectID: "project", RepoID: "a"},
+		{ProjectID: "project", RepoID: "b"},
+		{ProjectID: "project", RepoID: "c"},
+	}
+
+	result, err := client.FetchPullRequests(context.Background(), requests, User{}, 2)
+	if err != nil {
+		t.Fatalf("FetchPullRequests() error = %v, want the failure in Errors", err)
+	}
+
+	if got := prTitles(result.Prs); strings.Join(got, ",") != "a #1,c #1" {
+		t.Errorf("FetchPullRequests() = %q, want the pull requests of a and c", got)
+	}
+	if len(result.Errors) != 1 {
+		t.Fatalf("Errors = %v, want the error of b", result.Errors)
+	}
+	repoErr := result.Errors[0]
+	if repoErr.ProjectID != "project" || repoErr.RepoID != "b" {
+		t.Errorf("Errors[0] is of %s/%s, want project/b", repoErr.ProjectID, repoErr.RepoID)
+	}
+	var responseErr *ResponseError
+	if !errors.As(repoErr, &responseErr) || responseErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Errors[0] = %v, want a 404 *ResponseError", repoErr)
+	}
+	if want := "404 Not Found: The repository b does not exist."; repoErr.Reason() != want {
+		t.Errorf("Reason() = %q, want %q", repoErr.Reason(), want)
+	}
+}
+
+func TestFetchPullRequestsByProjectPages(t *testing.T) {
+	type page struct {
+		top  string
+		skip string
+	}
+
+	tests := []struct {
+		name        string
+		count       int
+		request     FetchPRRequest
+		wantIds     []int
+		wantHasMore bool
+		wantPages   []page
+	}{
+		{
+			name:      "pages until a short page",
+			count:     5,
+			request:   FetchPRRequest{PageSize: 2},
+			wantIds:   []int{1, 2, 3, 4, 5},
+			wantPages: []page{{"2", "0"}, {"2", "2"}, {"2", "4"}},
+		},
+		{
+			name:      "empty last page",
+			count:     4,
+			request:   FetchPRRequest{PageSize: 2},
+			wantIds:   []int{1, 2, 3, 4},
+			wantPages: []page{{"2", "0"}, {"2", "2"}, {"2", "4"}},
+		},
+		{
+			name:      "default page size",
+			count:     3,
+			wantIds:   []int{1, 2, 3},
+			wantPages: []page{{strconv.Itoa(DefaultPageSize), "0"}},
+		},
+		{
+			name:      "exactly the limit",
+			count:     4,
+			request:   FetchPRRequest{PageSize: 10, Limit: 4},
+			wantIds:   []int{1, 2, 3, 4},
+			wantPages: []page{{"5", "0"}},
+		},
+		{
+			name:        "more than the limit",
+			count:       5,
+			request:     FetchPRRequest{PageSize: 10, Limit: 4},
+			wantIds:     []int{1, 2, 3, 4},
+			wantHasMore: true,
+			wantPages:   []page{{"5", "0"}},
+		},
+		{
+			name:        "limit across pages",
+			count:       6,
+			request:     FetchPRRequest{PageSize: 2, Limit: 4},
+			wantIds:     []int{1, 2, 3, 4},
+			wantHasMore: true,
+			wantPages:   []page{{"2", "0"}, {"2", "2"}, {"1", "4"}},
+		},
+		{
+			name:      "limit across pages without more",
+			count:     4,
+			request:   FetchPRRequest{PageSize: 2, Limit: 4},
+			wantIds:   []int{1, 2, 3, 4},
+			wantPages: []page{{"2", "0"}, {"2", "2"}, {"1", "4"}},
+		},
+		{
+			name:        "next page",
+			count:       5,
+			request:     FetchPRRequest{Skip: 2, PageSize: 10, Limit: 2},
+			wantIds:     []int{3, 4},
+			wantHasMore: true,
+			wantPages:   []page{{"3", "2"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &pullRequestServer{counts: map[string]int{"repo": test.count}}
+			client := newPullRequestClient(t, server)
+			request := test.request
+			request.ProjectID = "project"
+			request.RepoID = "repo"
+
+			response, hasMore, err := client.FetchPullRequestsByProject(context.Background(), request)
+			if err != nil {
+				t.Fatalf("FetchPullRequestsByProject() error = %v", err)
+			}
+
+			var ids []int
+			for _, pr := range response.Value {
+				ids = append(ids, pr.PullRequestID)
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(test.wantIds) || response.Count != len(test.wantIds) {
+				t.Errorf("FetchPullRequestsByProject() = %v (count %d), want %v", ids, response.Count, test.wantIds)
+			}
+			if hasMore != test.wantHasMore {
+				t.Errorf("hasMore = %v, want %v", hasMore, test.wantHasMore)
+			}
+			var pages []page
+			for _, query := range server.queries {
+				pages = append(pages, page{query.Get("$top"), query.Get("$skip")})
+			}
+			if fmt.Sprint(pages) != fmt.Sprint(test.wantPages) {
+				t.Errorf("requested pages ($top, $skip) = %v, want %v", pages, test.wantPages)
+			}
+		})
+	}
+}
+
+func TestFetchPullRequestsContinuesWithNextPageRequests(t *testing.T) {
+	server := &pullRequestServer{counts: map[string]int{"a": 3, "b": 1}}
+	client := newPullRequestClient(t, server)
+	requests := []FetchPRRequest{
+		{ProjectID: "project", RepoID: "a", Limit: 2},
+		{ProjectID: "project", RepoID: "b", Limit: 2},
+	}
+
+	var titles []string
+	for page := 0; len(requests) > 0; page++ {
+		if page == 3 {
+			t.Fatalf("still has next page requests %+v", requests)
+		}
+		result, err := client.FetchPullRequests(context.Background(), requests, User{}, 2)
+		if err != nil {
+			t.Fatalf("FetchPullRequests() error = %v", err)
+		}
+		if len(result.Prs) == 0 {
+			t.Errorf("page %d is empty", page)
+		}
+		titles = append(titles, prTitles(result.Prs)...)
+		requests = result.NextPageRequests
+	}
+
+	if want := "a #1,a #2,b #1,a #3"; strings.Join(titles, ",") != want {
+		t.Errorf("pages = %q, want %q", titles, want)
+	}
+}
+
+func TestGetUserReview(t *testing.T) {
+	user := User{ID: "user-id", Groups: []string{"team-id"}}
+
+	tests := []struct {
+		name         string
+		reviewers    []ReviewerResponse
+		wantVote     int
+		wantRequired bool
+	}{
+		{
+			name: "not a reviewer",
+			reviewers: []ReviewerResponse{
+				{ID: "other-id", Vote: 10, IsRequired: true},
+			},
+		},
+		{
+			name: "optional reviewer",
+			reviewers: []ReviewerResponse{
+				{ID: "user-id", Vote: 5},
+			},
+			wantVote: 5,
+		},
+		{
+			name: "required reviewer",
+			reviewers: []ReviewerResponse{
+				{ID: "user-id", Vote: -10, IsRequired: true},
+			},
+			wantVote:     -10,
+			wantRequired: true,
+		},
+		{
+			name: "member of a required team without a vote",
+			reviewers: []ReviewerResponse{
+				{ID: "team-id", IsContainer: true, IsRequired: true},
+			},
+			wantRequired: true,
+		},
+		{
+			name: "member of an optional team",
+			reviewers: []ReviewerResponse{
+				{ID: "team-id", IsContainer: true},
+			},
+		},
+		{
+			name: "voted for a required group",
+			reviewers: []ReviewerResponse{
+				{ID: "group-id", IsContainer: true, IsRequired: true},
+				{ID: "user-id", Vote: 10, VotedFor: []ReviewerResponse{{ID: "group-id"}}},
+			},
+			wantVote:     10,
+			wantRequired: true,
+		},
+		{
+			name: "required group of someone else",
+			reviewers: []ReviewerResponse{
+				{ID: "group-id", IsContainer: true, IsRequired: true},
+				{ID: "user-id", Vote: 10},
+			},
+			wantVote: 10,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vote, isRequired := getUserReview(test.reviewers, user)
+			if vote != test.wantVote || isRequired != test.wantRequired {
+				t.Errorf("getUserReview() = %d, %v, want %d, %v", vote, isRequired, test.wantVote, test.wantRequired)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	VoteApproved                = 10
+	VoteApprovedWithSuggestions = 5
+	VoteNone                    = 0
+	VoteWaitingForAuthor        = -5
+	VoteRejected                = -10
+)
+
+const (
+	MergeStrategySquash        = "squash"
+	MergeStrategyNoFastForward = "noFastForward"
+	MergeStrategyRebase        = "rebase"
+	MergeStrategyRebaseMerge   = "rebaseMerge"
+)
+
+// mergePollInterval and mergePollAttempts bound how long CompletePullRequest
+// waits for the server to merge a pull request.
+const (
+	mergePollInterval = time.Second
+	mergePollAttempts = 15
+)
+
+type CompletionOptions struct {
+	MergeStrategy       string `json:"mergeStrategy,omitempty"`
+	DeleteSourceBranch  bool   `json:"deleteSourceBranch"`
+	TransitionWorkItems bool   `json:"transitionWorkItems"`
+}
+
+type completePullRequestRequest struct {
+	Status                string            `json:"status"`
+	LastMergeSourceCommit CommitResponse    `json:"lastMergeSourceCommit"`
+	CompletionOptions     CompletionOptions `json:"completionOptions"`
+}
+
+// noAutoCompleteSetBy is the identity that cancels auto-complete when set as
+// autoCompleteSetBy.
+const noAutoCompleteSetBy = "00000000-0000-0000-0000-000000000000"
+
+type identityRefRequest struct {
+	ID string `json:"id"`
+}
+
+type updatePullRequestRequest struct {
+	Status            string              `json:"status,omitempty"`
+	IsDraft           *bool               `json:"isDraft,omitempty"`
+	AutoCompleteSetBy *identityRefRequest `json:"autoCompleteSetBy,omitempty"`
+	CompletionOptions *CompletionOptions  `json:"completionOptions,omitempty"`
+}
+
+type reviewerVoteRequest struct {
+	Vote int `json:"vote"`
+}
+
+type addReviewerRequest struct {
+	Vote       int  `json:"vote"`
+	IsRequired bool `json:"isRequired"`
+}
+
+// pullRequestPath returns the path of a pull request's API resource.
+func pullRequestPath(pr PullRequestData) string {
+	return fmt.Sprintf(
+		"%s/_apis/git/repositories/%s/pullrequests/%d",
+		url.PathEscape(pr.ProjectID),
+		url.PathEscape(pr.RepositoryID),
+		pr.ID,
+	)
+}
+
+// Vote casts the vote of a reviewer on a pull request. The reviewer is added
+// to the pull request if it is not reviewing it yet.
+func (c *Client) Vote(ctx context.Context, pr PullRequestData, reviewerID string, vote int) error {
+	return c.do(ctx, "PUT", c.url(reviewerPath(pr, reviewerID), nil), reviewerVoteRequest{Vote: vote}, nil)
+}
+
+func reviewerPath(pr PullRequestData, reviewerID string) string {
+	return fmt.Sprintf("%s/reviewers/%s", pullRequestPath(pr), url.PathEscape(reviewerID))
+}
+
+// AddReviewer adds a user or group as an optional or required reviewer of a
+// pull request.
+func (c *Client) AddReviewer(ctx context.Context, pr PullRequestData, reviewerID string, isRequired bool) error {
+	body := addReviewerRequest{Vote: VoteNone, IsRequired: isRequired}
+	for _, reviewer := range pr.Reviewers {
+		// Keep the vote of a reviewer that is only made required.
+		if reviewer.ID == reviewerID {
+			body.Vote = reviewer.Vote
+		}
+	}
+
+	return c.do(ctx, "PUT", c.url(reviewerPath(pr, reviewerID), nil), body, nil)
+}
+
+// RemoveReviewer removes a reviewer and its vote from a pull request.
+func (c *Client) RemoveReviewer(ctx context.Context, pr PullRequestData, reviewerID string) error {
+	return c.do(ctx, "DELETE", c.url(reviewerPath(pr, reviewerID), nil), nil, nil)
+}
+
+func (c *Client) updatePullRequest(ctx context.Context, pr PullRequestData, body updatePullRequestRequest) error {
+	return c.do(ctx, "PATCH", c.url(pullRequestPath(pr), nil), body, nil)
+}
+
+// AbandonPullRequest abandons an active pull request.
+func (c *Client) AbandonPullRequest(ctx context.Context, pr PullRequestData) error {
+	return c.updatePullRequest(ctx, pr, updatePullRequestRequest{Status: PullRequestStatusAbandoned})
+}
+
+// ReactivatePullRequest makes an abandoned pull request active again.
+func (c *Client) ReactivatePullRequest(ctx context.Context, pr PullRequestData) error {
+	return c.updatePullRequest(ctx, pr, updatePullRequestRequest{Status: PullRequestStatusActive})
+}
+
+// SetDraft turns a pull request into a draft or publishes it for review.
+func (c *Client) SetDraft(ctx context.Context, pr PullRequestData, isDraft bool) error {
+	return c.updatePullRequest(ctx, pr, updatePullRequestRequest{IsDraft: &isDraft})
+}
+
+// SetAutoComplete lets the server complete a pull request with options on
+// behalf of the user once all of its policies pass.
+func (c *Client) SetAutoComplete(ctx context.Context, pr PullRequestData, userID string, options CompletionOptions) error {
+	return c.updatePullRequest(ctx, pr, updatePullRequestRequest{
+		AutoCompleteSetBy: &identityRefRequest{ID: userID},
+		CompletionOptions: &options,
+	})
+}
+
+// CancelAutoComplete stops a pull request from completing automatically.
+func (c *Client) CancelAutoComplete(ctx context.Context, pr PullRequestData) error {
+	return c.updatePullRequest(ctx, pr, updatePullRequestRequest{
+		AutoCompleteSetBy: &identityRefRequest{ID: noAutoCompleteSetBy},
+	})
+}
+
+// CompletePullRequest merges a pull request and waits until the server either
+// completed it or gave up merging it. Merge conflicts and rejections by
+// branch policies are returned as errors.
+func (c *Client) CompletePullRequest(ctx context.Context, pr PullRequestData, options CompletionOptions) error {
+	if pr.LastMergeSourceCommit == "" {
+		return errors.New("the last merge source commit of the pull request is unknown")
+	}
+
+	body := completePullRequestRequest{
+		Status:                PullRequestStatusCompleted,
+		LastMergeSourceCommit: CommitResponse{CommitID: pr.LastMergeSourceCommit},
+		CompletionOptions:     options,
+	}
+
+	var response PullRequestResponse
+	err := c.do(ctx, "PATCH", c.url(pullRequestPath(pr), nil), body, &response)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 0; attempt < mergePollAttempts; attempt++ {
+		if response.Status == PullRequestStatusCompleted {
+			return nil
+		}
+		if err = mergeError(response); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(mergePollInterval):
+		}
+
+		err = c.do(ctx, "GET", c.url(pullRequestPath(pr), nil), nil, &response)
+		if err != nil {
+			return err
+		}
+	}
+
+	return fmt.Errorf("the merge is still %s, check the pull request later", response.MergeStatus)
+}
+
+// mergeError describes why the server could not merge a pull request, or
+// returns nil while the merge is still pending.
+func mergeError(response PullRequestResponse) error {
+	reason := ""
+	switch response.MergeStatus {
+	case MergeStatusConflicts:
+		reason = "the pull request has merge conflicts"
+	case MergeStatusRejectedByPolicy:
+		reason = "the merge was rejected by a branch policy"
+	case MergeStatusFailure:
+		reason = "the merge failed"
+	default:
+		return nil
+	}
+
+	if response.MergeFailureMessage != "" {
+		return fmt.Errorf("%s: %s", reason, response.MergeFailureMessage)
+	}
+	return errors.New(reason)
+}
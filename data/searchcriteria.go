@@ -0,0 +1,98 @@
+package data
+
+import (
+	"azdo-dash/config"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	PullRequestStatusActive    = "active"
+	PullRequestStatusCompleted = "completed"
+	PullRequestStatusAbandoned = "abandoned"
+	PullRequestStatusAll       = "all"
+)
+
+const (
+	QueryTimeRangeCreated = "created"
+	QueryTimeRangeClosed  = "closed"
+)
+
+// SearchCriteria narrows down the pull requests of a repository on the
+// server. Empty fields are not sent, so the server defaults apply, e.g. only
+// active pull requests are returned when Status is empty.
+type SearchCriteria struct {
+	Status        string
+	CreatorID     string
+	ReviewerID    string
+	SourceRefName string
+	TargetRefName string
+	MinTime       *time.Time
+	MaxTime       *time.Time
+	// QueryTimeRangeType decides whether MinTime and MaxTime compare against
+	// the creation or the closing date.
+	QueryTimeRangeType string
+}
+
+// NewSearchCriteria maps the filters of a section to search criteria. "@me"
+// stands for user, Since and Until are subtracted from now.
+func NewSearchCriteria(filters config.PrFilters, user User, now time.Time) SearchCriteria {
+	resolveIdentity := func(identity string) string {
+		if identity == config.CurrentUserAlias && user.ID != "" {
+			return user.ID
+		}
+		return identity
+	}
+
+	criteria := SearchCriteria{
+		Status:             filters.Status,
+		CreatorID:          resolveIdentity(filters.Creator),
+		ReviewerID:         resolveIdentity(filters.Reviewer),
+		SourceRefName:      filters.SourceBranch,
+		TargetRefName:      filters.TargetBranch,
+		QueryTimeRangeType: filters.TimeRange,
+	}
+
+	if filters.Since > 0 {
+		minTime := now.Add(-filters.Since)
+		criteria.MinTime = &minTime
+	}
+	if filters.Until > 0 {
+		maxTime := now.Add(-filters.Until)
+		criteria.MaxTime = &maxTime
+	}
+
+	return criteria
+}
+
+func (s SearchCriteria) setQuery(query url.Values) {
+	set := func(key string, value string) {
+		if value != "" {
+			query.Set("searchCriteria."+key, value)
+		}
+	}
+
+	set("status", s.Status)
+	set("creatorId", s.CreatorID)
+	set("reviewerId", s.ReviewerID)
+	set("sourceRefName", toRefName(s.SourceRefName))
+	set("targetRefName", toRefName(s.TargetRefName))
+	if s.MinTime != nil {
+		set("minTime", s.MinTime.UTC().Format(time.RFC3339))
+	}
+	if s.MaxTime != nil {
+		set("maxTime", s.MaxTime.UTC().Format(time.RFC3339))
+	}
+	if s.MinTime != nil || s.MaxTime != nil {
+		set("queryTimeRangeType", s.QueryTimeRangeType)
+	}
+}
+
+// toRefName turns a branch name into a full ref name as the API expects it.
+func toRefName(branch string) string {
+	if branch == "" || strings.HasPrefix(branch, "refs/") {
+		return branch
+	}
+	return "refs/heads/" + branch
+}
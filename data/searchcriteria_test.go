@@ -0,0 +1,115 @@
+package data
+
+import (
+	"azdo-dash/config"
+	"context"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestSearchCriteriaQuery(t *testing.T) {
+	user := User{ID: "user-id"}
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	tests := []struct {
+		name    string
+		filters config.PrFilters
+		user    User
+		want    url.Values
+	}{
+		{
+			name:    "no filters",
+			filters: config.PrFilters{},
+			user:    user,
+			want:    url.Values{},
+		},
+		{
+			name:    "status",
+			filters: config.PrFilters{Status: PullRequestStatusCompleted},
+			user:    user,
+			want:    url.Values{"searchCriteria.status": {"completed"}},
+		},
+		{
+			name:    "@me",
+			filters: config.PrFilters{Creator: config.CurrentUserAlias, Reviewer: config.CurrentUserAlias},
+			user:    user,
+			want: url.Values{
+				"searchCriteria.creatorId":  {"user-id"},
+				"searchCriteria.reviewerId": {"user-id"},
+			},
+		},
+		{
+			name:    "@me without a user",
+			filters: config.PrFilters{Creator: config.CurrentUserAlias},
+			want:    url.Values{"searchCriteria.creatorId": {"@me"}},
+		},
+		{
+			name:    "identity ids",
+			filters: config.PrFilters{Creator: "creator-id", Reviewer: "reviewer-id"},
+			user:    user,
+			want: url.Values{
+				"searchCriteria.creatorId":  {"creator-id"},
+				"searchCriteria.reviewerId": {"reviewer-id"},
+			},
+		},
+		{
+			name:    "branches",
+			filters: config.PrFilters{SourceBranch: "feature/login", TargetBranch: "refs/heads/main"},
+			user:    user,
+			want: url.Values{
+				"searchCriteria.sourceRefName": {"refs/heads/feature/login"},
+				"searchCriteria.targetRefName": {"refs/heads/main"},
+			},
+		},
+		{
+			name: "time range",
+			filters: config.PrFilters{
+				Status:    PullRequestStatusCompleted,
+				Since:     7 * 24 * time.Hour,
+				Until:     time.Hour,
+				TimeRange: QueryTimeRangeClosed,
+			},
+			user: user,
+			want: url.Values{
+				"searchCriteria.status":             {"completed"},
+				"searchCriteria.minTime":            {"2024-03-03T11:00:00Z"},
+				"searchCriteria.maxTime":            {"2024-03-10T10:00:00Z"},
+				"searchCriteria.queryTimeRangeType": {"closed"},
+			},
+		},
+		{
+			name:    "time range type without times",
+			filters: config.PrFilters{TimeRange: QueryTimeRangeCreated},
+			user:    user,
+			want:    url.Values{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := &pullRequestServer{}
+			client := newPullRequestClient(t, server)
+
+			_, _, err := client.FetchPullRequestsByProject(context.Background(), FetchPRRequest{
+				ProjectID:      "project",
+				RepoID:         "repo",
+				SearchCriteria: NewSearchCriteria(test.filters, test.user, now),
+			})
+			if err != nil {
+				t.Fatalf("FetchPullRequestsByProject() error = %v", err)
+			}
+			if len(server.queries) != 1 {
+				t.Fatalf("server got %d requests, want 1", len(server.queries))
+			}
+
+			query := server.queries[0]
+			for _, key := range []string{"$top", "$skip", "api-version"} {
+				query.Del(key)
+			}
+			if query.Encode() != test.want.Encode() {
+				t.Errorf("query = %s, want %s", query.Encode(), test.want.Encode())
+			}
+		})
+	}
+}
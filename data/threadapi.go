@@ -0,0 +1,211 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	ThreadStatusActive   = "active"
+	ThreadStatusFixed    = "fixed"
+	ThreadStatusWontFix  = "wontFix"
+	ThreadStatusClosed   = "closed"
+	ThreadStatusByDesign = "byDesign"
+	ThreadStatusPending  = "pending"
+)
+
+const (
+	CommentTypeText   = "text"
+	CommentTypeSystem = "system"
+)
+
+type Thread struct {
+	ID       int
+	Status   string
+	FilePath string
+	// Line is the line of the file in the source branch the thread is
+	// about, or 0 for threads about the whole file or pull request.
+	Line            int
+	Comments        []Comment
+	LastUpdatedDate time.Time
+}
+
+// IsSystem reports whether the thread was created by the server, e.g. when a
+// reviewer voted, rather than by a user.
+func (t Thread) IsSystem() bool {
+	for _, comment := range t.Comments {
+		if comment.CommentType != CommentTypeSystem {
+			return false
+		}
+	}
+	return true
+}
+
+type Comment struct {
+	ID              int
+	ParentCommentID int
+	Author          string
+	Content         string
+	CommentType     string
+	PublishedDate   time.Time
+}
+
+type FetchThreadsResponse struct {
+	Value []ThreadResponse `json:"value"`
+}
+
+type ThreadResponse struct {
+	ID              int                    `json:"id"`
+	Status          string                 `json:"status"`
+	ThreadContext   *ThreadContextResponse `json:"threadContext"`
+	Comments        []CommentResponse      `json:"comments"`
+	IsDeleted       bool                   `json:"isDeleted"`
+	LastUpdatedDate time.Time              `json:"lastUpdatedDate"`
+}
+
+type ThreadContextResponse struct {
+	FilePath       string                `json:"filePath"`
+	RightFileStart *FilePositionResponse `json:"rightFileStart"`
+	LeftFileStart  *FilePositionResponse `json:"leftFileStart"`
+}
+
+type FilePositionResponse struct {
+	Line   int `json:"line"`
+	Offset int `json:"offset"`
+}
+
+type CommentResponse struct {
+	ID              int          `json:"id"`
+	ParentCommentID int          `json:"parentCommentId"`
+	Author          UserResponse `json:"author"`
+	Content         string       `json:"content"`
+	CommentType     string       `json:"commentType"`
+	PublishedDate   time.Time    `json:"publishedDate"`
+	IsDeleted       bool         `json:"isDeleted"`
+}
+
+type createCommentRequest struct {
+	ParentCommentID int    `json:"parentCommentId"`
+	Content         string `json:"content"`
+	CommentType     string `json:"commentType"`
+}
+
+type createThreadRequest struct {
+	Comments []createCommentRequest `json:"comments"`
+	Status   string                 `json:"status"`
+}
+
+type updateThreadRequest struct {
+	Status string `json:"status"`
+}
+
+func threadsPath(pr PullRequestData) string {
+	return fmt.Sprintf("%s/threads", pullRequestPath(pr))
+}
+
+func threadPath(pr PullRequestData, threadID int) string {
+	return fmt.Sprintf("%s/%d", threadsPath(pr), threadID)
+}
+
+// FetchThreads fetches the comment threads of a pull request, leaving out
+// deleted threads and comments.
+func (c *Client) FetchThreads(ctx context.Context, pr PullRequestData) ([]Thread, error) {
+	var response FetchThreadsResponse
+	err := c.do(ctx, "GET", c.url(threadsPath(pr), nil), nil, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	threads := make([]Thread, 0, len(response.Value))
+	for _, threadResponse := range response.Value {
+		if threadResponse.IsDeleted {
+			continue
+		}
+		threads = append(threads, newThread(threadResponse))
+	}
+
+	return threads, nil
+}
+
+// CountUnresolvedThreads counts the active threads of a pull request that
+// were written by users.
+func (c *Client) CountUnresolvedThreads(ctx context.Context, pr PullRequestData) (int, error) {
+	threads, err := c.FetchThreads(ctx, pr)
+	if err != nil {
+		return 0, err
+	}
+	return UnresolvedThreadCount(threads), nil
+}
+
+// UnresolvedThreadCount counts the active threads written by users.
+func UnresolvedThreadCount(threads []Thread) int {
+	count := 0
+	for _, thread := range threads {
+		if thread.Status == ThreadStatusActive && !thread.IsSystem() {
+			count++
+		}
+	}
+	return count
+}
+
+func newThread(response ThreadResponse) Thread {
+	thread := Thread{
+		ID:              response.ID,
+		Status:          response.Status,
+		LastUpdatedDate: response.LastUpdatedDate,
+	}
+
+	if response.ThreadContext != nil {
+		thread.FilePath = response.ThreadContext.FilePath
+		switch {
+		case response.ThreadContext.RightFileStart != nil:
+			thread.Line = response.ThreadContext.RightFileStart.Line
+		case response.ThreadContext.LeftFileStart != nil:
+			thread.Line = response.ThreadContext.LeftFileStart.Line
+		}
+	}
+
+	for _, comment := range response.Comments {
+		if comment.IsDeleted {
+			continue
+		}
+		thread.Comments = append(thread.Comments, Comment{
+			ID:              comment.ID,
+			ParentCommentID: comment.ParentCommentID,
+			Author:          comment.Author.DisplayName,
+			Content:         comment.Content,
+			CommentType:     comment.CommentType,
+			PublishedDate:   comment.PublishedDate,
+		})
+	}
+
+	return thread
+}
+
+// ReplyToThread adds a comment to a thread in reply to the comment with
+// parentCommentID.
+func (c *Client) ReplyToThread(ctx context.Context, pr PullRequestData, threadID int, parentCommentID int, content string) error {
+	body := createCommentRequest{
+		ParentCommentID: parentCommentID,
+		Content:         content,
+		CommentType:     CommentTypeText,
+	}
+
+	return c.do(ctx, "POST", c.url(threadPath(pr, threadID)+"/comments", nil), body, nil)
+}
+
+// CreateThread starts an active thread about the pull request as a whole.
+func (c *Client) CreateThread(ctx context.Context, pr PullRequestData, content string) error {
+	body := createThreadRequest{
+		Comments: []createCommentRequest{{Content: content, CommentType: CommentTypeText}},
+		Status:   ThreadStatusActive,
+	}
+
+	return c.do(ctx, "POST", c.url(threadsPath(pr), nil), body, nil)
+}
+
+// SetThreadStatus resolves or reactivates a thread.
+func (c *Client) SetThreadStatus(ctx context.Context, pr PullRequestData, threadID int, status string) error {
+	return c.do(ctx, "PATCH", c.url(threadPath(pr, threadID), nil), updateThreadRequest{Status: status}, nil)
+}
@@ -0,0 +1,108 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+type User struct {
+	ID          string
+	DisplayName string
+	UniqueName  string
+	// Groups are the ids of the groups and teams the user is a direct or
+	// indirect member of, see FetchGroups.
+	Groups []string
+}
+
+type ConnectionDataResponse struct {
+	AuthenticatedUser IdentityResponse `json:"authenticatedUser"`
+}
+
+type IdentityResponse struct {
+	ID                  string                              `json:"id"`
+	ProviderDisplayName string                              `json:"providerDisplayName"`
+	Properties          map[string]IdentityPropertyResponse `json:"properties"`
+}
+
+type IdentityPropertyResponse struct {
+	Value string `json:"$value"`
+}
+
+// FetchCurrentUser resolves the identity the client is authenticated as.
+func (c *Client) FetchCurrentUser(ctx context.Context) (User, error) {
+	query := url.Values{}
+	query.Set("api-version", c.previewVersion())
+
+	var response ConnectionDataResponse
+	err := c.do(ctx, "GET", c.url("_apis/connectionData", query), nil, &response)
+	if err != nil {
+		return User{}, err
+	}
+
+	if response.AuthenticatedUser.ID == "" {
+		return User{}, fmt.Errorf("no authenticated user in connection data")
+	}
+
+	return User{
+		ID:          response.AuthenticatedUser.ID,
+		DisplayName: response.AuthenticatedUser.ProviderDisplayName,
+		UniqueName:  response.AuthenticatedUser.Properties["Account"].Value,
+	}, nil
+}
+
+type IdentitiesResponse struct {
+	Value []MemberIdentityResponse `json:"value"`
+}
+
+type MemberIdentityResponse struct {
+	ID       string   `json:"id"`
+	MemberOf []string `json:"memberOf"`
+}
+
+// groupsBatchSize is the number of group descriptors resolved per request,
+// it keeps the address of the request short enough.
+const groupsBatchSize = 50
+
+// FetchGroups resolves the ids of the groups and teams user is a direct or
+// indirect member of. These are the ids pull requests list group reviewers
+// by.
+func (c *Client) FetchGroups(ctx context.Context, user User) ([]string, error) {
+	query := url.Values{}
+	query.Set("identityIds", user.ID)
+	query.Set("queryMembership", "expanded")
+
+	var response IdentitiesResponse
+	err := c.do(ctx, "GET", c.url("_apis/identities", query), nil, &response)
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Value) == 0 {
+		return nil, fmt.Errorf("no identity with the id %s", user.ID)
+	}
+
+	// The memberships are listed by descriptor, the groups are looked up
+	// for their ids.
+	descriptors := response.Value[0].MemberOf
+	groups := make([]string, 0, len(descriptors))
+	for start := 0; start < len(descriptors); start += groupsBatchSize {
+		batch := descriptors[start:min(start+groupsBatchSize, len(descriptors))]
+		query := url.Values{}
+		query.Set("descriptors", strings.Join(batch, ","))
+
+		var groupsResponse IdentitiesResponse
+		err := c.do(ctx, "GET", c.url("_apis/identities", query), nil, &groupsResponse)
+		if err != nil {
+			return nil, err
+		}
+		for _, group := range groupsResponse.Value {
+			// Descriptors that cannot be resolved come back empty.
+			if group.ID != "" {
+				groups = append(groups, group.ID)
+			}
+		}
+	}
+
+	return groups, nil
+}
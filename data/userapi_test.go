@@ -0,0 +1,54 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFetchGroups(t *testing.T) {
+	// The user is a member of more groups than are resolved per request.
+	var memberOf []string
+	groups := map[string]string{}
+	var want []string
+	for i := 0; i < groupsBatchSize+2; i++ {
+		descriptor := fmt.Sprintf("Microsoft.TeamFoundation.Identity;S-1-9-%d", i)
+		memberOf = append(memberOf, descriptor)
+		groups[descriptor] = fmt.Sprintf("group-%d", i)
+		want = append(want, groups[descriptor])
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case query.Get("identityIds") == "user-id" && query.Get("queryMembership") == "expanded":
+			fmt.Fprintf(w, `{"value": [{"id": "user-id", "memberOf": ["%s"]}]}`, strings.Join(memberOf, `","`))
+		case query.Has("descriptors"):
+			var values []string
+			for _, descriptor := range strings.Split(query.Get("descriptors"), ",") {
+				values = append(values, fmt.Sprintf(`{"id": %q}`, groups[descriptor]))
+			}
+			// Unknown descriptors come back as null.
+			values = append(values, "null")
+			fmt.Fprintf(w, `{"value": [%s]}`, strings.Join(values, ","))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "org", "", nil)
+	client.HTTPClient = server.Client()
+
+	got, err := client.FetchGroups(context.Background(), User{ID: "user-id"})
+	if err != nil {
+		t.Fatalf("FetchGroups() error = %v", err)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("FetchGroups() = %v, want %v", got, want)
+	}
+}
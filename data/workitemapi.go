@@ -0,0 +1,242 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// workItemsBatchSize is the number of work items the API returns at most per
+// request.
+const workItemsBatchSize = 200
+
+// workItemFields are the fields fetched of every work item.
+var workItemFields = []string{
+	"System.Title",
+	"System.WorkItemType",
+	"System.State",
+	"System.AssignedTo",
+	"System.IterationPath",
+	"System.TeamProject",
+}
+
+const (
+	workItemStateField      = "/fields/System.State"
+	workItemAssignedToField = "/fields/System.AssignedTo"
+)
+
+type WorkItem struct {
+	ID            int
+	Title         string
+	Type          string
+	State         string
+	AssignedTo    string
+	IterationPath string
+	ProjectName   string
+}
+
+type ResourceRefsResponse struct {
+	Value []ResourceRefResponse `json:"value"`
+}
+
+type ResourceRefResponse struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+}
+
+type wiqlRequest struct {
+	Query string `json:"query"`
+}
+
+// jsonPatchOperation changes a work item. Requests with a body of
+// operations are sent with doPatch.
+type jsonPatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value"`
+}
+
+type WiqlResponse struct {
+	WorkItems []WorkItemReferenceResponse `json:"workItems"`
+}
+
+type WorkItemReferenceResponse struct {
+	ID int `json:"id"`
+}
+
+type WorkItemStatesResponse struct {
+	Value []WorkItemStateResponse `json:"value"`
+}
+
+type WorkItemStateResponse struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
+
+type WorkItemsResponse struct {
+	// Value has null entries for work items that could not be fetched.
+	Value []*WorkItemResponse `json:"value"`
+}
+
+type WorkItemResponse struct {
+	ID     int                    `json:"id"`
+	Fields WorkItemFieldsResponse `json:"fields"`
+}
+
+type WorkItemFieldsResponse struct {
+	Title         string        `json:"System.Title"`
+	WorkItemType  string        `json:"System.WorkItemType"`
+	State         string        `json:"System.State"`
+	AssignedTo    *UserResponse `json:"System.AssignedTo"`
+	IterationPath string        `json:"System.IterationPath"`
+	TeamProject   string        `json:"System.TeamProject"`
+}
+
+// FetchPullRequestWorkItems fetches the work items linked to a pull request.
+func (c *Client) FetchPullRequestWorkItems(ctx context.Context, pr PullRequestData) ([]WorkItem, error) {
+	var response ResourceRefsResponse
+	err := c.do(ctx, "GET", c.url(pullRequestPath(pr)+"/workitems", nil), nil, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(response.Value))
+	for _, ref := range response.Value {
+		if id, err := strconv.Atoi(ref.ID); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	return c.FetchWorkItems(ctx, ids)
+}
+
+// FetchWorkItems fetches work items by their ids, in the order of ids. Work
+// items that do not exist or are not visible to the user are left out.
+func (c *Client) FetchWorkItems(ctx context.Context, ids []int) ([]WorkItem, error) {
+	workItems := make([]WorkItem, 0, len(ids))
+	for start := 0; start < len(ids); start += workItemsBatchSize {
+		batch := ids[start:min(len(ids), start+workItemsBatchSize)]
+		idTexts := make([]string, 0, len(batch))
+		for _, id := range batch {
+			idTexts = append(idTexts, strconv.Itoa(id))
+		}
+
+		query := url.Values{}
+		query.Set("ids", strings.Join(idTexts, ","))
+		query.Set("fields", strings.Join(workItemFields, ","))
+		query.Set("errorPolicy", "omit")
+
+		var response WorkItemsResponse
+		err := c.do(ctx, "GET", c.url("_apis/wit/workitems", query), nil, &response)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, workItem := range response.Value {
+			if workItem != nil {
+				workItems = append(workItems, newWorkItem(*workItem))
+			}
+		}
+	}
+
+	return workItems, nil
+}
+
+// QueryWorkItems runs a WIQL query and fetches the first limit work items it
+// returns, in the order of the query. Queries using the @project macro need a
+// project to run in, others may leave it empty.
+func (c *Client) QueryWorkItems(ctx context.Context, project string, query string, limit int) ([]WorkItem, error) {
+	path := "_apis/wit/wiql"
+	if project != "" {
+		path = url.PathEscape(project) + "/" + path
+	}
+	apiQuery := url.Values{}
+	if limit > 0 {
+		apiQuery.Set("$top", strconv.Itoa(limit))
+	}
+
+	var response WiqlResponse
+	err := c.do(ctx, "POST", c.url(path, apiQuery), wiqlRequest{Query: query}, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(response.WorkItems))
+	for _, ref := range response.WorkItems {
+		ids = append(ids, ref.ID)
+	}
+
+	return c.FetchWorkItems(ctx, ids)
+}
+
+// FetchWorkItemStates fetches the states a work item of the type of
+// workItem can be in.
+func (c *Client) FetchWorkItemStates(ctx context.Context, workItem WorkItem) ([]string, error) {
+	path := fmt.Sprintf(
+		"%s/_apis/wit/workitemtypes/%s/states",
+		url.PathEscape(workItem.ProjectName),
+		url.PathEscape(workItem.Type),
+	)
+
+	var response WorkItemStatesResponse
+	err := c.do(ctx, "GET", c.url(path, nil), nil, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]string, 0, len(response.Value))
+	for _, state := range response.Value {
+		states = append(states, state.Name)
+	}
+	return states, nil
+}
+
+// SetWorkItemState moves a work item to state and returns the updated work
+// item.
+func (c *Client) SetWorkItemState(ctx context.Context, workItem WorkItem, state string) (WorkItem, error) {
+	return c.updateWorkItem(ctx, workItem, []jsonPatchOperation{
+		{Op: "add", Path: workItemStateField, Value: state},
+	})
+}
+
+// AssignWorkItem assigns a work item to the user with uniqueName, or
+// unassigns it if uniqueName is empty, and returns the updated work item.
+func (c *Client) AssignWorkItem(ctx context.Context, workItem WorkItem, uniqueName string) (WorkItem, error) {
+	return c.updateWorkItem(ctx, workItem, []jsonPatchOperation{
+		{Op: "add", Path: workItemAssignedToField, Value: uniqueName},
+	})
+}
+
+func (c *Client) updateWorkItem(ctx context.Context, workItem WorkItem, operations []jsonPatchOperation) (WorkItem, error) {
+	path := fmt.Sprintf("_apis/wit/workitems/%d", workItem.ID)
+
+	var response WorkItemResponse
+	err := c.doPatch(ctx, c.url(path, nil), operations, &response)
+	if err != nil {
+		return WorkItem{}, err
+	}
+	return newWorkItem(response), nil
+}
+
+func newWorkItem(response WorkItemResponse) WorkItem {
+	fields := response.Fields
+	workItem := WorkItem{
+		ID:            response.ID,
+		Title:         fields.Title,
+		Type:          fields.WorkItemType,
+		State:         fields.State,
+		IterationPath: fields.IterationPath,
+		ProjectName:   fields.TeamProject,
+	}
+	if fields.AssignedTo != nil {
+		workItem.AssignedTo = fields.AssignedTo.DisplayName
+	}
+	return workItem
+}
+
+// WorkItemWebURL returns the address of the work item in the web portal.
+func (c *Client) WorkItemWebURL(workItem WorkItem) string {
+	return c.webURL(workItem.ProjectName, "_workitems", "edit", strconv.Itoa(workItem.ID))
+}
@@ -0,0 +1,246 @@
+package diff
+
+import (
+	"strings"
+)
+
+type Op int
+
+const (
+	Equal Op = iota
+	Insert
+	Delete
+)
+
+// Line is a line of a diff. OldNumber and NewNumber are the 1-based line
+// numbers in the old and new text, 0 for lines missing from either.
+type Line struct {
+	Op        Op
+	OldNumber int
+	NewNumber int
+	Text      string
+}
+
+// Hunk is a group of changed lines with the unchanged lines around them.
+type Hunk struct {
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	Lines    []Line
+}
+
+// maxEditDistance bounds the work of the diff. Texts that differ by more
+// lines are diffed as the removal of the old and the addition of the new
+// lines between their common beginning and end.
+const maxEditDistance = 1000
+
+// SplitLines splits text into lines without their line breaks.
+func SplitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// Lines diffs the lines of two texts.
+func Lines(oldLines []string, newLines []string) []Line {
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]Op, 0, len(oldLines)+len(newLines))
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, Equal)
+	}
+	ops = append(ops, myers(oldLines[prefix:len(oldLines)-suffix], newLines[prefix:len(newLines)-suffix])...)
+	for i := 0; i < suffix; i++ {
+		ops = append(ops, Equal)
+	}
+
+	lines := make([]Line, 0, len(ops))
+	oldIndex, newIndex := 0, 0
+	for _, op := range ops {
+		line := Line{Op: op}
+		switch op {
+		case Equal:
+			line.Text = newLines[newIndex]
+			oldIndex++
+			newIndex++
+			line.OldNumber = oldIndex
+			line.NewNumber = newIndex
+		case Delete:
+			line.Text = oldLines[oldIndex]
+			oldIndex++
+			line.OldNumber = oldIndex
+		case Insert:
+			line.Text = newLines[newIndex]
+			newIndex++
+			line.NewNumber = newIndex
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// myers returns the shortest edit script turning a into b, see "An O(ND)
+// Difference Algorithm and Its Variations" by Eugene W. Myers.
+func myers(a []string, b []string) []Op {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return replace(n, m)
+	}
+
+	maxD := min(n+m, maxEditDistance)
+	offset := maxD + 1
+	v := make([]int, 2*offset+1)
+	// trace keeps the furthest reaching paths before every step d.
+	var trace [][]int
+
+	for d := 0; d <= maxD; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+
+			if x >= n && y >= m {
+				return backtrack(trace, n, m)
+			}
+		}
+	}
+
+	return replace(n, m)
+}
+
+// backtrack walks the paths of trace back from the end of both texts.
+func backtrack(trace [][]int, n int, m int) []Op {
+	var ops []Op
+	x, y := n, m
+
+	for d := len(trace) - 1; d > 0; d-- {
+		// trace[d] holds the diagonals -d to d.
+		v := func(k int) int {
+			return trace[d][k+d]
+		}
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v(k-1) < v(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			ops = append(ops, Equal)
+			x--
+			y--
+		}
+		if x == prevX {
+			ops = append(ops, Insert)
+		} else {
+			ops = append(ops, Delete)
+		}
+		x, y = prevX, prevY
+	}
+
+	for x > 0 && y > 0 {
+		ops = append(ops, Equal)
+		x--
+		y--
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
+
+func replace(n int, m int) []Op {
+	ops := make([]Op, 0, n+m)
+	for i := 0; i < n; i++ {
+		ops = append(ops, Delete)
+	}
+	for i := 0; i < m; i++ {
+		ops = append(ops, Insert)
+	}
+	return ops
+}
+
+// Hunks groups the changed lines with up to context unchanged lines around
+// them. Changes at most two contexts apart share a hunk.
+func Hunks(lines []Line, context int) []Hunk {
+	var hunks []Hunk
+
+	i := 0
+	for i < len(lines) {
+		if lines[i].Op == Equal {
+			i++
+			continue
+		}
+
+		start := max(0, i-context)
+		end := i
+		for end < len(lines) {
+			if lines[end].Op != Equal {
+				end++
+				continue
+			}
+			// Look ahead whether the next change is close enough.
+			next := end
+			for next < len(lines) && lines[next].Op == Equal {
+				next++
+			}
+			if next == len(lines) || next-end > 2*context {
+				end = min(len(lines), end+context)
+				break
+			}
+			end = next
+		}
+
+		hunks = append(hunks, newHunk(lines[start:end]))
+		i = end
+	}
+
+	return hunks
+}
+
+func newHunk(lines []Line) Hunk {
+	hunk := Hunk{Lines: lines}
+	for _, line := range lines {
+		if line.Op != Insert {
+			if hunk.OldStart == 0 {
+				hunk.OldStart = line.OldNumber
+			}
+			hunk.OldLines++
+		}
+		if line.Op != Delete {
+			if hunk.NewStart == 0 {
+				hunk.NewStart = line.NewNumber
+			}
+			hunk.NewLines++
+		}
+	}
+	return hunk
+}
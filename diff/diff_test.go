@@ -0,0 +1,241 @@
+package diff
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name      string
+		old       string
+		new       string
+		wantEdits int
+	}{
+		{"both empty", "", "", 0},
+		{"identical", "a\nb\nc", "a\nb\nc", 0},
+		{"added file", "", "a\nb", 2},
+		{"deleted file", "a\nb", "", 2},
+		{"insert at start", "b\nc", "a\nb\nc", 1},
+		{"delete at end", "a\nb\nc", "a\nb", 1},
+		{"replace in the middle", "a\nb\nc", "a\nx\nc", 2},
+		{"nothing in common", "a\nb", "c\nd", 4},
+		{"paper example", "a\nb\nc\na\nb\nb\na", "c\nb\na\nb\na\nc", 5},
+		{"moved line", "a\nb\nc\nd", "b\nc\nd\na", 2},
+		{"repeated lines", "x\nx\nx", "x\ny\nx\nx", 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			oldLines := strings.Split(test.old, "\n")
+			newLines := strings.Split(test.new, "\n")
+			if test.old == "" {
+				oldLines = nil
+			}
+			if test.new == "" {
+				newLines = nil
+			}
+
+			lines := Lines(oldLines, newLines)
+
+			var gotOld, gotNew []string
+			edits := 0
+			oldNumber, newNumber := 0, 0
+			for _, line := range lines {
+				if line.Op != Equal {
+					edits++
+				}
+				if line.Op == Insert {
+					if line.OldNumber != 0 {
+						t.Errorf("inserted line %q has old number %d", line.Text, line.OldNumber)
+					}
+				} else {
+					gotOld = append(gotOld, line.Text)
+					oldNumber++
+					if line.OldNumber != oldNumber {
+						t.Errorf("line %q has old number %d, want %d", line.Text, line.OldNumber, oldNumber)
+					}
+				}
+				if line.Op == Delete {
+					if line.NewNumber != 0 {
+						t.Errorf("deleted line %q has new number %d", line.Text, line.NewNumber)
+					}
+				} else {
+					gotNew = append(gotNew, line.Text)
+					newNumber++
+					if line.NewNumber != newNumber {
+						t.Errorf("line %q has new number %d, want %d", line.Text, line.NewNumber, newNumber)
+					}
+				}
+			}
+
+			if !slices.Equal(gotOld, oldLines) {
+				t.Errorf("old lines of the diff = %q, want %q", gotOld, oldLines)
+			}
+			if !slices.Equal(gotNew, newLines) {
+				t.Errorf("new lines of the diff = %q, want %q", gotNew, newLines)
+			}
+			if edits != test.wantEdits {
+				t.Errorf("diff has %d edits, want %d", edits, test.wantEdits)
+			}
+		})
+	}
+}
+
+func TestLinesFallsBackBeyondMaxEditDistance(t *testing.T) {
+	// Every other line changes, so the shortest edit script is longer than
+	// maxEditDistance. Only the first and the last line are kept.
+	n := maxEditDistance + 1001
+	oldLines := make([]string, 0, n)
+	newLines := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		oldLines = append(oldLines, fmt.Sprintf("line %d", i))
+		if i%2 == 1 {
+			newLines = append(newLines, fmt.Sprintf("changed %d", i))
+		} else {
+			newLines = append(newLines, fmt.Sprintf("line %d", i))
+		}
+	}
+
+	lines := Lines(oldLines, newLines)
+
+	changed := n - 2
+	want := []Op{Equal}
+	for i := 0; i < changed; i++ {
+		want = append(want, Delete)
+	}
+	for i := 0; i < changed; i++ {
+		want = append(want, Insert)
+	}
+	want = append(want, Equal)
+
+	ops := make([]Op, 0, len(lines))
+	for _, line := range lines {
+		ops = append(ops, line.Op)
+	}
+	if !slices.Equal(ops, want) {
+		t.Fatalf("diff has %d lines, want the %d lines of deleting and inserting everything in between", len(ops), len(want))
+	}
+	if last := lines[len(lines)-1]; last.OldNumber != n || last.NewNumber != n {
+		t.Errorf("last line has numbers %d and %d, want %d", last.OldNumber, last.NewNumber, n)
+	}
+}
+
+func TestHunks(t *testing.T) {
+	// numbered returns the lines "1" to "n" with the lines at changes
+	// replaced.
+	numbered := func(n int, changes ...int) []string {
+		lines := make([]string, 0, n)
+		for i := 1; i <= n; i++ {
+			if slices.Contains(changes, i) {
+				lines = append(lines, fmt.Sprintf("changed %d", i))
+			} else {
+				lines = append(lines, fmt.Sprint(i))
+			}
+		}
+		return lines
+	}
+
+	tests := []struct {
+		name    string
+		changes []int
+		context int
+		want    []Hunk
+	}{
+		{
+			name: "no changes",
+			want: nil,
+		},
+		{
+			name:    "change in the middle",
+			changes: []int{10},
+			context: 3,
+			want:    []Hunk{{OldStart: 7, OldLines: 7, NewStart: 7, NewLines: 7}},
+		},
+		{
+			name:    "leading context cut at the start",
+			changes: []int{2},
+			context: 3,
+			want:    []Hunk{{OldStart: 1, OldLines: 5, NewStart: 1, NewLines: 5}},
+		},
+		{
+			name:    "trailing context cut at the end",
+			changes: []int{19},
+			context: 3,
+			want:    []Hunk{{OldStart: 16, OldLines: 5, NewStart: 16, NewLines: 5}},
+		},
+		{
+			name:    "changes with touching contexts share a hunk",
+			changes: []int{5, 12},
+			context: 3,
+			want:    []Hunk{{OldStart: 2, OldLines: 14, NewStart: 2, NewLines: 14}},
+		},
+		{
+			name:    "changes within two contexts share a hunk",
+			changes: []int{5, 11},
+			context: 3,
+			want:    []Hunk{{OldStart: 2, OldLines: 13, NewStart: 2, NewLines: 13}},
+		},
+		{
+			name:    "changes further apart get their own hunks",
+			changes: []int{5, 13},
+			context: 3,
+			want: []Hunk{
+				{OldStart: 2, OldLines: 7, NewStart: 2, NewLines: 7},
+				{OldStart: 10, OldLines: 7, NewStart: 10, NewLines: 7},
+			},
+		},
+		{
+			name:    "no context",
+			changes: []int{5, 6},
+			context: 0,
+			want:    []Hunk{{OldStart: 5, OldLines: 2, NewStart: 5, NewLines: 2}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hunks := Hunks(Lines(numbered(20), numbered(20, test.changes...)), test.context)
+
+			if len(hunks) != len(test.want) {
+				t.Fatalf("Hunks() = %d hunks, want %d", len(hunks), len(test.want))
+			}
+			for i, hunk := range hunks {
+				want := test.want[i]
+				if hunk.OldStart != want.OldStart || hunk.OldLines != want.OldLines ||
+					hunk.NewStart != want.NewStart || hunk.NewLines != want.NewLines {
+					t.Errorf(
+						"hunk %d = -%d,%d +%d,%d, want -%d,%d +%d,%d",
+						i,
+						hunk.OldStart, hunk.OldLines, hunk.NewStart, hunk.NewLines,
+						want.OldStart, want.OldLines, want.NewStart, want.NewLines,
+					)
+				}
+				if first := hunk.Lines[0]; test.context > 0 && first.Op != Equal {
+					t.Errorf("hunk %d starts with a change, want context", i)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", nil},
+		{"a", []string{"a"}},
+		{"a\n", []string{"a"}},
+		{"a\r\nb\r\n", []string{"a", "b"}},
+		{"a\n\nb", []string{"a", "", "b"}},
+	}
+
+	for _, test := range tests {
+		if got := SplitLines(test.text); !slices.Equal(got, test.want) {
+			t.Errorf("SplitLines(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ErrCloneNotFound is returned by FindClone if no clone has the remote.
+var ErrCloneNotFound = errors.New("no local clone found")
+
+// Remote is a remote of a local repository.
+type Remote struct {
+	Name string
+	URL  string
+}
+
+// run runs git in dir and returns its trimmed output. Errors carry what git
+// printed to stderr.
+func run(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	// There is no terminal to ask for credentials in.
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], message)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// Remotes returns the remotes of the repository in dir.
+func Remotes(ctx context.Context, dir string) ([]Remote, error) {
+	output, err := run(ctx, dir, "remote", "-v")
+	if err != nil {
+		return nil, err
+	}
+
+	var remotes []Remote
+	for _, line := range strings.Split(output, "\n") {
+		// Every remote is listed once for fetching and once for pushing.
+		fields := strings.Fields(line)
+		if len(fields) == 3 && fields[2] == "(fetch)" {
+			remotes = append(remotes, Remote{Name: fields[0], URL: fields[1]})
+		}
+	}
+
+	return remotes, nil
+}
+
+// FindRemote returns the name of the remote of the repository in dir that
+// points to remoteURL, or an empty name if there is none.
+func FindRemote(ctx context.Context, dir string, remoteURL string) (string, error) {
+	remotes, err := Remotes(ctx, dir)
+	if err != nil {
+		return "", err
+	}
+
+	for _, remote := range remotes {
+		if SameRemoteURL(remote.URL, remoteURL) {
+			return remote.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// FindClone looks for a repository with a remote pointing to remoteURL among
+// dirs and their subdirectories.
+func FindClone(ctx context.Context, dirs []string, remoteURL string) (string, error) {
+	for _, dir := range dirs {
+		candidates := []string{dir}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+				candidates = append(candidates, filepath.Join(dir, entry.Name()))
+			}
+		}
+
+		for _, candidate := range candidates {
+			if _, err := os.Stat(filepath.Join(candidate, ".git")); err != nil {
+				continue
+			}
+			if remote, err := FindRemote(ctx, candidate, remoteURL); err == nil && remote != "" {
+				return candidate, nil
+			}
+		}
+	}
+
+	return "", ErrCloneNotFound
+}
+
+// SameRemoteURL tells whether two remote URLs point to the same repository.
+// The HTTPS and SSH URLs of an Azure DevOps repository count as the same, as
+// do its old visualstudio.com addresses.
+func SameRemoteURL(a string, b string) bool {
+	return normalizeRemoteURL(a) == normalizeRemoteURL(b)
+}
+
+// normalizeRemoteURL turns the forms of Azure DevOps remote URLs into
+// dev.azure.com/{org}/{project}/_git/{repo}. Other URLs only lose their
+// scheme, user and .git suffix.
+func normalizeRemoteURL(remoteURL string) string {
+	normalized := strings.TrimSpace(remoteURL)
+	if unescaped, err := url.PathUnescape(normalized); err == nil {
+		normalized = unescaped
+	}
+	normalized = strings.ToLower(normalized)
+	normalized = strings.TrimSuffix(strings.TrimSuffix(normalized, "/"), ".git")
+
+	if scheme := strings.Index(normalized, "://"); scheme >= 0 {
+		normalized = normalized[scheme+len("://"):]
+	}
+	if user := strings.Index(normalized, "@"); user >= 0 && user < strings.IndexAny(normalized+"/", ":/") {
+		normalized = normalized[user+1:]
+	}
+
+	switch {
+	// git@ssh.dev.azure.com:v3/{org}/{project}/{repo}
+	case strings.HasPrefix(normalized, "ssh.dev.azure.com:v3/"):
+		parts := strings.SplitN(strings.TrimPrefix(normalized, "ssh.dev.azure.com:v3/"), "/", 3)
+		if len(parts) == 3 {
+			return fmt.Sprintf("dev.azure.com/%s/%s/_git/%s", parts[0], parts[1], parts[2])
+		}
+
+	// {org}@vs-ssh.visualstudio.com:v3/{org}/{project}/{repo}
+	case strings.HasPrefix(normalized, "vs-ssh.visualstudio.com:v3/"):
+		parts := strings.SplitN(strings.TrimPrefix(normalized, "vs-ssh.visualstudio.com:v3/"), "/", 3)
+		if len(parts) == 3 {
+			return fmt.Sprintf("dev.azure.com/%s/%s/_git/%s", parts[0], parts[1], parts[2])
+		}
+
+	// {org}.visualstudio.com/[DefaultCollection/]{project}/_git/{repo}
+	case strings.Contains(normalized, ".visualstudio.com/"):
+		host, path, _ := strings.Cut(normalized, "/")
+		org := strings.TrimSuffix(host, ".visualstudio.com")
+		path = strings.TrimPrefix(path, "defaultcollection/")
+		return fmt.Sprintf("dev.azure.com/%s/%s", org, path)
+	}
+
+	return normalized
+}
+
+// hasLocalBranch tells whether the repository in dir has the branch.
+func hasLocalBranch(ctx context.Context, dir string, branch string) bool {
+	_, err := run(ctx, dir, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+	return err == nil
+}
+
+// fetch updates the remote tracking branch of branch.
+func fetch(ctx context.Context, dir string, remote string, branch string) error {
+	_, err := run(
+		ctx, dir, "fetch", remote,
+		fmt.Sprintf("+refs/heads/%s:refs/remotes/%s/%s", branch, remote, branch),
+	)
+	return err
+}
+
+// Checkout fetches branch from remote and checks it out in the repository in
+// dir. An existing local branch is fast-forwarded, local commits are never
+// thrown away.
+func Checkout(ctx context.Context, dir string, remote string, branch string) error {
+	if err := fetch(ctx, dir, remote, branch); err != nil {
+		return err
+	}
+
+	remoteBranch := remote + "/" + branch
+	if !hasLocalBranch(ctx, dir, branch) {
+		_, err := run(ctx, dir, "checkout", "-b", branch, "--track", remoteBranch)
+		return err
+	}
+
+	if _, err := run(ctx, dir, "checkout", branch); err != nil {
+		return err
+	}
+	_, err := run(ctx, dir, "merge", "--ff-only", remoteBranch)
+	return err
+}
+
+// CheckoutWorktree fetches branch from remote and checks it out in a
+// worktree of the repository in dir. A worktree that already has the branch
+// checked out is fast-forwarded, otherwise one is added below worktreesDir.
+// It returns the directory of the worktree.
+func CheckoutWorktree(ctx context.Context, dir string, remote string, branch string, worktreesDir string) (string, error) {
+	if err := fetch(ctx, dir, remote, branch); err != nil {
+		return "", err
+	}
+
+	remoteBranch := remote + "/" + branch
+	worktree, err := findWorktree(ctx, dir, branch)
+	if err != nil {
+		return "", err
+	}
+	if worktree != "" {
+		_, err := run(ctx, worktree, "merge", "--ff-only", remoteBranch)
+		return worktree, err
+	}
+
+	worktree = filepath.Join(worktreesDir, strings.ReplaceAll(branch, "/", "-"))
+	if !hasLocalBranch(ctx, dir, branch) {
+		_, err := run(ctx, dir, "worktree", "add", "--track", "-b", branch, worktree, remoteBranch)
+		return worktree, err
+	}
+
+	if _, err := run(ctx, dir, "worktree", "add", worktree, branch); err != nil {
+		return "", err
+	}
+	_, err = run(ctx, worktree, "merge", "--ff-only", remoteBranch)
+	return worktree, err
+}
+
+// findWorktree returns the directory of the worktree of the repository in
+// dir that has branch checked out, or an empty directory if there is none.
+func findWorktree(ctx context.Context, dir string, branch string) (string, error) {
+	output, err := run(ctx, dir, "worktree", "list", "--porcelain")
+	if err != nil {
+		return "", err
+	}
+
+	// Worktrees are listed as blocks of lines separated by empty lines.
+	var worktree string
+	for _, line := range strings.Split(output, "\n") {
+		switch {
+		case strings.HasPrefix(line, "worktree "):
+			worktree = strings.TrimPrefix(line, "worktree ")
+		case line == "branch refs/heads/"+branch:
+			return worktree, nil
+		}
+	}
+	return "", nil
+}
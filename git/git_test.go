@@ -0,0 +1,293 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// testRepos is a bare repository standing in for the Azure DevOps remote, a
+// clone to check out branches in and a second clone that pushes to the
+// remote.
+type testRepos struct {
+	remote string
+	clone  string
+	pusher string
+}
+
+func newTestRepos(t *testing.T) testRepos {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	// Keep the configuration of the machine out of the tests.
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	dir := t.TempDir()
+	repos := testRepos{
+		remote: filepath.Join(dir, "remote.git"),
+		clone:  filepath.Join(dir, "clones", "repo"),
+		pusher: filepath.Join(dir, "pusher"),
+	}
+
+	gitIn(t, dir, "init", "--bare", "--initial-branch=main", repos.remote)
+	gitIn(t, dir, "clone", repos.remote, repos.pusher)
+	commit(t, repos.pusher, "main", "initial commit")
+	gitIn(t, repos.pusher, "push", "origin", "main")
+	gitIn(t, dir, "clone", repos.remote, repos.clone)
+
+	return repos
+}
+
+func gitIn(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	output, err := run(context.Background(), dir, args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return output
+}
+
+// commit adds a commit to branch, creating it from the checked out branch
+// if it does not exist, and returns its hash.
+func commit(t *testing.T, dir string, branch string, message string) string {
+	t.Helper()
+	if !hasLocalBranch(context.Background(), dir, branch) {
+		gitIn(t, dir, "checkout", "-b", branch)
+	} else {
+		gitIn(t, dir, "checkout", branch)
+	}
+	gitIn(t, dir, "commit", "--allow-empty", "-m", message)
+	return gitIn(t, dir, "rev-parse", "HEAD")
+}
+
+// push commits to branch of the remote and returns the hash of the commit.
+func (r testRepos) push(t *testing.T, branch string, message string) string {
+	t.Helper()
+	hash := commit(t, r.pusher, branch, message)
+	gitIn(t, r.pusher, "push", "origin", branch)
+	return hash
+}
+
+func assertCheckedOut(t *testing.T, dir string, branch string, hash string) {
+	t.Helper()
+	if got := gitIn(t, dir, "rev-parse", "--abbrev-ref", "HEAD"); got != branch {
+		t.Errorf("checked out branch = %q, want %q", got, branch)
+	}
+	if got := gitIn(t, dir, "rev-parse", "HEAD"); got != hash {
+		t.Errorf("HEAD = %s, want %s", got, hash)
+	}
+}
+
+func TestCheckoutNewBranch(t *testing.T) {
+	repos := newTestRepos(t)
+	hash := repos.push(t, "feature/login", "add login")
+
+	if err := Checkout(context.Background(), repos.clone, "origin", "feature/login"); err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+
+	assertCheckedOut(t, repos.clone, "feature/login", hash)
+	upstream := gitIn(t, repos.clone, "rev-parse", "--abbrev-ref", "feature/login@{upstream}")
+	if upstream != "origin/feature/login" {
+		t.Errorf("upstream = %q, want origin/feature/login", upstream)
+	}
+}
+
+func TestCheckoutFastForwardsExistingBranch(t *testing.T) {
+	repos := newTestRepos(t)
+	ctx := context.Background()
+	repos.push(t, "feature", "first")
+	if err := Checkout(ctx, repos.clone, "origin", "feature"); err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+	gitIn(t, repos.clone, "checkout", "main")
+
+	hash := repos.push(t, "feature", "second")
+	if err := Checkout(ctx, repos.clone, "origin", "feature"); err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+
+	assertCheckedOut(t, repos.clone, "feature", hash)
+}
+
+func TestCheckoutKeepsLocalCommits(t *testing.T) {
+	repos := newTestRepos(t)
+	ctx := context.Background()
+	repos.push(t, "feature", "first")
+	if err := Checkout(ctx, repos.clone, "origin", "feature"); err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+	local := commit(t, repos.clone, "feature", "local")
+
+	repos.push(t, "feature", "remote")
+	if err := Checkout(ctx, repos.clone, "origin", "feature"); err == nil {
+		t.Fatal("Checkout() of a diverged branch succeeded, want an error")
+	}
+
+	if got := gitIn(t, repos.clone, "rev-parse", "feature"); got != local {
+		t.Errorf("feature = %s, want the local commit %s", got, local)
+	}
+}
+
+func TestCheckoutWorktree(t *testing.T) {
+	repos := newTestRepos(t)
+	ctx := context.Background()
+	worktreesDir := repos.clone + ".worktrees"
+	hash := repos.push(t, "feature/login", "add login")
+
+	worktree, err := CheckoutWorktree(ctx, repos.clone, "origin", "feature/login", worktreesDir)
+	if err != nil {
+		t.Fatalf("CheckoutWorktree() error = %v", err)
+	}
+	if want := filepath.Join(worktreesDir, "feature-login"); worktree != want {
+		t.Errorf("CheckoutWorktree() = %q, want %q", worktree, want)
+	}
+	assertCheckedOut(t, worktree, "feature/login", hash)
+	// The clone itself stays on its branch.
+	if got := gitIn(t, repos.clone, "rev-parse", "--abbrev-ref", "HEAD"); got != "main" {
+		t.Errorf("clone has %q checked out, want main", got)
+	}
+
+	// Checking out again finds the worktree and fast-forwards it.
+	hash = repos.push(t, "feature/login", "fix login")
+	again, err := CheckoutWorktree(ctx, repos.clone, "origin", "feature/login", worktreesDir)
+	if err != nil {
+		t.Fatalf("CheckoutWorktree() error = %v", err)
+	}
+	if again != worktree {
+		t.Errorf("CheckoutWorktree() = %q, want the existing worktree %q", again, worktree)
+	}
+	assertCheckedOut(t, worktree, "feature/login", hash)
+}
+
+func TestFindWorktree(t *testing.T) {
+	repos := newTestRepos(t)
+	ctx := context.Background()
+	repos.push(t, "feature", "first")
+
+	if worktree, err := findWorktree(ctx, repos.clone, "feature"); err != nil || worktree != "" {
+		t.Errorf("findWorktree() = %q, %v, want no worktree", worktree, err)
+	}
+
+	added, err := CheckoutWorktree(ctx, repos.clone, "origin", "feature", t.TempDir())
+	if err != nil {
+		t.Fatalf("CheckoutWorktree() error = %v", err)
+	}
+	worktree, err := findWorktree(ctx, repos.clone, "feature")
+	if err != nil {
+		t.Fatalf("findWorktree() error = %v", err)
+	}
+	// git lists the worktrees with symlinks resolved.
+	if !sameDir(t, worktree, added) {
+		t.Errorf("findWorktree() = %q, want %q", worktree, added)
+	}
+}
+
+func sameDir(t *testing.T, a string, b string) bool {
+	t.Helper()
+	aInfo, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bInfo, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(aInfo, bInfo)
+}
+
+func TestFindClone(t *testing.T) {
+	repos := newTestRepos(t)
+	ctx := context.Background()
+	searchDir := filepath.Dir(repos.clone)
+	if err := os.Mkdir(filepath.Join(searchDir, "not-a-repo"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	clone, err := FindClone(ctx, []string{t.TempDir(), searchDir}, repos.remote)
+	if err != nil {
+		t.Fatalf("FindClone() error = %v", err)
+	}
+	if clone != repos.clone {
+		t.Errorf("FindClone() = %q, want %q", clone, repos.clone)
+	}
+
+	// The clone itself can be searched as well as its parent.
+	if clone, err := FindClone(ctx, []string{repos.clone}, repos.remote); err != nil || clone != repos.clone {
+		t.Errorf("FindClone() = %q, %v, want %q", clone, err, repos.clone)
+	}
+
+	_, err = FindClone(ctx, []string{searchDir}, repos.remote+"-other")
+	if !errors.Is(err, ErrCloneNotFound) {
+		t.Errorf("FindClone() of another remote error = %v, want ErrCloneNotFound", err)
+	}
+}
+
+func TestFindRemote(t *testing.T) {
+	repos := newTestRepos(t)
+	ctx := context.Background()
+	gitIn(t, repos.clone, "remote", "add", "upstream", "git@ssh.dev.azure.com:v3/org/project/repo")
+
+	tests := []struct {
+		remoteURL string
+		want      string
+	}{
+		{repos.remote, "origin"},
+		{"https://dev.azure.com/org/project/_git/repo", "upstream"},
+		{"https://dev.azure.com/org/project/_git/other", ""},
+	}
+
+	for _, test := range tests {
+		got, err := FindRemote(ctx, repos.clone, test.remoteURL)
+		if err != nil {
+			t.Fatalf("FindRemote(%q) error = %v", test.remoteURL, err)
+		}
+		if got != test.want {
+			t.Errorf("FindRemote(%q) = %q, want %q", test.remoteURL, got, test.want)
+		}
+	}
+}
+
+func TestNormalizeRemoteURL(t *testing.T) {
+	const want = "dev.azure.com/org/project/_git/repo"
+
+	tests := []struct {
+		name      string
+		remoteURL string
+		want      string
+	}{
+		{"https", "https://dev.azure.com/org/project/_git/repo", want},
+		{"https with user", "https://org@dev.azure.com/org/project/_git/repo", want},
+		{"https with .git and slash", "https://dev.azure.com/org/project/_git/repo.git/", want},
+		{"https mixed case", "https://dev.azure.com/Org/Project/_git/Repo", want},
+		{"https escaped", "https://dev.azure.com/org/my%20project/_git/repo", "dev.azure.com/org/my project/_git/repo"},
+		{"ssh", "git@ssh.dev.azure.com:v3/org/project/repo", want},
+		{"ssh with scheme", "ssh://git@ssh.dev.azure.com:v3/org/project/repo", want},
+		{"visualstudio.com", "https://org.visualstudio.com/project/_git/repo", want},
+		{"visualstudio.com with user", "https://org@org.visualstudio.com/project/_git/repo", want},
+		{"visualstudio.com default collection", "https://org.visualstudio.com/DefaultCollection/project/_git/repo", want},
+		{"visualstudio.com ssh", "org@vs-ssh.visualstudio.com:v3/org/project/repo", want},
+		{"other host", "https://github.com/owner/repo.git", "github.com/owner/repo"},
+		{"other ssh host", "git@github.com:owner/repo.git", "github.com:owner/repo"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := normalizeRemoteURL(test.remoteURL); got != test.want {
+				t.Errorf("normalizeRemoteURL(%q) = %q, want %q", test.remoteURL, got, test.want)
+			}
+		})
+	}
+}
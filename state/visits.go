@@ -0,0 +1,136 @@
+package state
+
+import (
+	"azdo-dash/config"
+	"azdo-dash/data"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const visitsFileName = "visits.json"
+
+const DEFAULT_XDG_STATE_DIRNAME = ".local/state"
+
+// Visit records what the user has seen of a pull request. Iterations are 0
+// until the user visited or voted for the first time.
+type Visit struct {
+	// VisitedIteration is the latest iteration when the user last looked at
+	// the changes of the pull request.
+	VisitedIteration int `json:"visitedIteration"`
+	// VotedIteration is the latest iteration when the user last voted.
+	VotedIteration int `json:"votedIteration"`
+}
+
+// LastSeenIteration returns the latest iteration the user visited or voted
+// on, or 0 if there is none.
+func (v Visit) LastSeenIteration() int {
+	return max(v.VisitedIteration, v.VotedIteration)
+}
+
+// Visits are the visits of pull requests, stored in the state directory so
+// they survive restarts. They are safe for concurrent use.
+type Visits struct {
+	path   string
+	mu     sync.Mutex
+	visits map[string]Visit
+}
+
+// LoadVisits reads the visits stored in $XDG_STATE_HOME/azdo-dash. A missing
+// file is no error, the visits are empty then. The visits are usable even if
+// loading fails, they are only kept in memory if the directory is unknown.
+func LoadVisits() (*Visits, error) {
+	stateDir := os.Getenv("XDG_STATE_HOME")
+	if stateDir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return &Visits{visits: map[string]Visit{}}, err
+		}
+		stateDir = filepath.Join(homeDir, DEFAULT_XDG_STATE_DIRNAME)
+	}
+
+	visits := &Visits{
+		path:   filepath.Join(stateDir, config.DashDir, visitsFileName),
+		visits: map[string]Visit{},
+	}
+
+	content, err := os.ReadFile(visits.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return visits, nil
+	}
+	if err != nil {
+		return visits, err
+	}
+	if err := json.Unmarshal(content, &visits.visits); err != nil {
+		return visits, fmt.Errorf("failed parsing %s: %w", visits.path, err)
+	}
+
+	return visits, nil
+}
+
+// Get returns the visit of a pull request.
+func (v *Visits) Get(pr data.PullRequestData) Visit {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.visits[visitKey(pr)]
+}
+
+// RecordVisit records that the user looked at the changes of a pull request
+// up to iteration.
+func (v *Visits) RecordVisit(pr data.PullRequestData, iteration int) error {
+	return v.update(pr, func(visit *Visit) {
+		visit.VisitedIteration = max(visit.VisitedIteration, iteration)
+	})
+}
+
+// RecordVote records that the user voted on a pull request when iteration
+// was its latest.
+func (v *Visits) RecordVote(pr data.PullRequestData, iteration int) error {
+	return v.update(pr, func(visit *Visit) {
+		visit.VotedIteration = iteration
+	})
+}
+
+func (v *Visits) update(pr data.PullRequestData, update func(visit *Visit)) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	key := visitKey(pr)
+	visit := v.visits[key]
+	update(&visit)
+	v.visits[key] = visit
+
+	return v.save()
+}
+
+func (v *Visits) save() error {
+	if v.path == "" {
+		return nil
+	}
+
+	content, err := json.MarshalIndent(v.visits, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(v.path), os.ModePerm); err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, so a crash never leaves a truncated
+	// file behind.
+	tmpPath := v.path + ".tmp"
+	if err := os.WriteFile(tmpPath, content, 0666); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, v.path)
+}
+
+// visitKey identifies a pull request by its repository, whose id is unique
+// across organizations.
+func visitKey(pr data.PullRequestData) string {
+	return fmt.Sprintf("%s/%d", pr.RepositoryID, pr.ID)
+}
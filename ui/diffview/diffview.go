@@ -0,0 +1,242 @@
+package diffview
+
+import (
+	"azdo-dash/data"
+	"azdo-dash/diff"
+	"bytes"
+	"fmt"
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around changes.
+const contextLines = 3
+
+// maxHighlightSize is the size up to which files are syntax highlighted.
+const maxHighlightSize = 512 * 1024
+
+const tabWidth = 4
+
+var highlightStyle = styles.Get("monokai")
+
+var (
+	hunkHeaderStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("6")).Faint(true)
+	insertStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	deleteStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	renameStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+	editStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	gutterStyle     = lipgloss.NewStyle().Faint(true)
+	faintStyle      = lipgloss.NewStyle().Faint(true)
+	selectedStyle   = lipgloss.NewStyle().Bold(true).Background(lipgloss.Color("237"))
+)
+
+// InitializeHighlightStyle picks the syntax highlighting style matching the
+// terminal background. It has to be called before the first diff is created.
+func InitializeHighlightStyle(hasDarkBackground bool) {
+	if hasDarkBackground {
+		highlightStyle = styles.Get("monokai")
+	} else {
+		highlightStyle = styles.Get("github")
+	}
+}
+
+// File is the diff of a changed file, highlighted and ready to be rendered.
+type File struct {
+	Change   data.FileChange
+	IsBinary bool
+	Hunks    []diff.Hunk
+	// oldLines and newLines are the highlighted lines of the file before and
+	// after the change.
+	oldLines []string
+	newLines []string
+}
+
+// NewFile diffs the content of a file before and after a change.
+func NewFile(change data.FileChange, oldContent []byte, newContent []byte) File {
+	file := File{Change: change}
+	if isBinary(oldContent) || isBinary(newContent) {
+		file.IsBinary = true
+		return file
+	}
+
+	oldText := expandTabs(string(oldContent))
+	newText := expandTabs(string(newContent))
+	lines := diff.Lines(diff.SplitLines(oldText), diff.SplitLines(newText))
+	file.Hunks = diff.Hunks(lines, contextLines)
+	file.oldLines = highlight(change.OriginalPath, oldText)
+	file.newLines = highlight(change.Path, newText)
+
+	return file
+}
+
+// isBinary guesses whether content is binary the way git does, by looking
+// for a NUL byte at its beginning.
+func isBinary(content []byte) bool {
+	return bytes.IndexByte(content[:min(len(content), 8000)], 0) >= 0
+}
+
+func expandTabs(text string) string {
+	return strings.ReplaceAll(text, "\t", strings.Repeat(" ", tabWidth))
+}
+
+// highlight splits text into syntax highlighted lines. Tokens spanning
+// several lines are styled on each of them.
+func highlight(path string, text string) []string {
+	if len(text) > maxHighlightSize {
+		return diff.SplitLines(text)
+	}
+
+	lexer := lexers.Match(path)
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	iterator, err := chroma.Coalesce(lexer).Tokenise(nil, text)
+	if err != nil {
+		return diff.SplitLines(text)
+	}
+
+	tokenStyles := map[chroma.TokenType]lipgloss.Style{}
+	var lines []string
+	line := strings.Builder{}
+	for _, token := range iterator.Tokens() {
+		style, ok := tokenStyles[token.Type]
+		if !ok {
+			style = tokenStyle(highlightStyle.Get(token.Type))
+			tokenStyles[token.Type] = style
+		}
+
+		parts := strings.Split(strings.ReplaceAll(token.Value, "\r\n", "\n"), "\n")
+		for i, part := range parts {
+			if i > 0 {
+				lines = append(lines, line.String())
+				line.Reset()
+			}
+			if part != "" {
+				line.WriteString(style.Render(part))
+			}
+		}
+	}
+	if line.Len() > 0 {
+		lines = append(lines, line.String())
+	}
+
+	return lines
+}
+
+func tokenStyle(entry chroma.StyleEntry) lipgloss.Style {
+	style := lipgloss.NewStyle()
+	if entry.Colour.IsSet() {
+		style = style.Foreground(lipgloss.Color(entry.Colour.String()))
+	}
+	if entry.Bold == chroma.Yes {
+		style = style.Bold(true)
+	}
+	if entry.Italic == chroma.Yes {
+		style = style.Italic(true)
+	}
+	return style
+}
+
+// View renders the diff of the file cut off at width. It also returns the
+// line each hunk starts at, so the caller can jump between them.
+func (f File) View(width int) (string, []int) {
+	if f.IsBinary {
+		return faintStyle.Render("Binary file"), nil
+	}
+	if len(f.Hunks) == 0 {
+		return faintStyle.Render("No changes in the content"), nil
+	}
+
+	numberWidth := len(fmt.Sprint(max(len(f.oldLines), len(f.newLines))))
+	s := strings.Builder{}
+	offsets := make([]int, 0, len(f.Hunks))
+	lineCount := 0
+	for _, hunk := range f.Hunks {
+		offsets = append(offsets, lineCount)
+		s.WriteString(hunkHeaderStyle.Render(fmt.Sprintf(
+			"@@ -%d,%d +%d,%d @@",
+			hunk.OldStart, hunk.OldLines, hunk.NewStart, hunk.NewLines,
+		)))
+		s.WriteString("\n")
+		lineCount++
+
+		for _, line := range hunk.Lines {
+			s.WriteString(ansi.Truncate(f.viewLine(line, numberWidth), width, ""))
+			s.WriteString("\n")
+			lineCount++
+		}
+	}
+
+	return s.String(), offsets
+}
+
+func (f File) viewLine(line diff.Line, numberWidth int) string {
+	number := func(n int) string {
+		if n == 0 {
+			return strings.Repeat(" ", numberWidth)
+		}
+		return fmt.Sprintf("%*d", numberWidth, n)
+	}
+	gutter := fmt.Sprintf("%s %s ", number(line.OldNumber), number(line.NewNumber))
+
+	switch line.Op {
+	case diff.Insert:
+		return insertStyle.Render(gutter+"+ ") + lineAt(f.newLines, line.NewNumber, line.Text)
+	case diff.Delete:
+		return deleteStyle.Render(gutter+"- ") + lineAt(f.oldLines, line.OldNumber, line.Text)
+	default:
+		return gutterStyle.Render(gutter+"  ") + lineAt(f.newLines, line.NewNumber, line.Text)
+	}
+}
+
+// lineAt returns the highlighted line with number, or text if the file could
+// not be highlighted that far.
+func lineAt(lines []string, number int, text string) string {
+	if number < 1 || number > len(lines) {
+		return text
+	}
+	return lines[number-1]
+}
+
+// FileList renders the paths of the changed files, one per line, cut off at
+// width.
+func FileList(changes []data.FileChange, cursor int, width int) string {
+	s := strings.Builder{}
+	for i, change := range changes {
+		marker := changeMarker(change.ChangeType)
+		path := change.Path
+		// Keep the end of long paths, the file name matters most.
+		if available := width - 3; ansi.StringWidth(path) > available && available > 1 {
+			runes := []rune(path)
+			for len(runes) > 0 && ansi.StringWidth(string(runes)) > available-1 {
+				runes = runes[1:]
+			}
+			path = "…" + string(runes)
+		}
+
+		line := fmt.Sprintf("%s %s", marker, path)
+		if i == cursor {
+			line = selectedStyle.Render(fmt.Sprintf("%s %s", ansi.Strip(marker), path))
+		}
+		s.WriteString(ansi.Truncate(line, width, ""))
+		s.WriteString("\n")
+	}
+	return s.String()
+}
+
+func changeMarker(changeType string) string {
+	switch {
+	case strings.Contains(changeType, data.ChangeTypeAdd):
+		return insertStyle.Render("A")
+	case strings.Contains(changeType, data.ChangeTypeDelete):
+		return deleteStyle.Render("D")
+	case strings.Contains(changeType, data.ChangeTypeRename):
+		return renameStyle.Render("R")
+	default:
+		return editStyle.Render("M")
+	}
+}
@@ -0,0 +1,36 @@
+package diffview
+
+import (
+	"azdo-dash/data"
+	"github.com/charmbracelet/x/ansi"
+	"strings"
+	"testing"
+)
+
+func TestFileListKeepsEndOfLongPaths(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  string
+		width int
+		want  string
+	}{
+		{"short path", "/src/main.go", 40, "/src/main.go"},
+		{"ascii path", "/src/very/long/directory/main.go", 20, "…irectory/main.go"},
+		{"wide characters", "/源代码/非常长的目录名称/另一个目录/文件.go", 38, "…常长的目录名称/另一个目录/文件.go"},
+		{"wide character at the cut", "/目录/文件.go", 10, "…件.go"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			changes := []data.FileChange{{Path: test.path, ChangeType: data.ChangeTypeEdit}}
+			line := strings.TrimSuffix(ansi.Strip(FileList(changes, -1, test.width)), "\n")
+
+			if got := strings.TrimPrefix(line, "M "); got != test.want {
+				t.Errorf("FileList() = %q, want %q", got, test.want)
+			}
+			if ansi.StringWidth(line) > test.width {
+				t.Errorf("FileList() is %d wide, want at most %d", ansi.StringWidth(line), test.width)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package identitypicker
+
+import (
+	"azdo-dash/data"
+	"azdo-dash/ui/keys"
+	"fmt"
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"strings"
+)
+
+// results is the number of search results the picker lists.
+const results = 5
+
+// Height is the number of lines the picker takes up: a border, the input,
+// the results and the help line.
+const Height = 1 + 1 + results + 1
+
+// Action tells the owner of the picker what to do after a key.
+type Action int
+
+const (
+	None Action = iota
+	// Search asks to search for Query with Search.
+	Search
+	// Pick tells that the user picked the Selected identity.
+	Pick
+	// Cancel tells that the user closed the picker.
+	Cancel
+)
+
+// IdentitiesFoundMsg carries the identities matching Query.
+type IdentitiesFoundMsg struct {
+	Query      string
+	Identities []data.User
+}
+
+// Model searches the identities of the organization for the user to pick
+// one. The search runs when enter is pressed on a query that was not
+// searched yet, pressing enter again picks the selected identity.
+type Model struct {
+	IsOpen        bool
+	input         textinput.Model
+	searchedQuery string
+	identities    []data.User
+	cursor        int
+}
+
+var (
+	pickerStyle = lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder(), true, false, false, false).
+			BorderForeground(lipgloss.Color("8")).
+			PaddingLeft(1)
+	selectedStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	faintStyle    = lipgloss.NewStyle().Faint(true)
+)
+
+// New returns an open picker whose input is labelled with prompt.
+func New(prompt string) Model {
+	input := textinput.New()
+	input.Prompt = prompt
+	input.Placeholder = "name or mail address"
+	input.Cursor.SetMode(cursor.CursorStatic)
+	input.Focus()
+
+	return Model{IsOpen: true, input: input}
+}
+
+func (m *Model) Close() {
+	m.IsOpen = false
+	m.input.Blur()
+}
+
+// Query returns the trimmed text of the input.
+func (m Model) Query() string {
+	return strings.TrimSpace(m.input.Value())
+}
+
+// Selected returns the selected identity, or false if there is none.
+func (m Model) Selected() (data.User, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.identities) {
+		return data.User{}, false
+	}
+	return m.identities[m.cursor], true
+}
+
+// Update handles a key while the picker is open.
+func (m *Model) Update(msg tea.KeyMsg) (Action, tea.Cmd) {
+	switch {
+	case key.Matches(msg, keys.InputKeys.Cancel):
+		m.Close()
+		return Cancel, nil
+
+	case key.Matches(msg, keys.InputKeys.Up):
+		m.cursor = max(0, m.cursor-1)
+		return None, nil
+
+	case key.Matches(msg, keys.InputKeys.Down):
+		m.cursor = max(0, min(m.cursor+1, len(m.identities)-1))
+		return None, nil
+
+	case key.Matches(msg, keys.InputKeys.Submit):
+		if query := m.Query(); query != m.searchedQuery {
+			if query == "" {
+				return None, nil
+			}
+			return Search, nil
+		}
+		if _, ok := m.Selected(); ok {
+			m.Close()
+			return Pick, nil
+		}
+		return None, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return None, cmd
+}
+
+// SetIdentities shows the results of a search.
+func (m *Model) SetIdentities(msg IdentitiesFoundMsg) {
+	// Drop results of a search the user typed past.
+	if !m.IsOpen || msg.Query != m.Query() {
+		return
+	}
+
+	m.searchedQuery = msg.Query
+	m.identities = msg.Identities
+	m.cursor = 0
+}
+
+// View renders the picker at width with help below the results.
+func (m Model) View(width int, help string) string {
+	if !m.IsOpen {
+		return ""
+	}
+
+	line := lipgloss.NewStyle().MaxWidth(max(0, width-pickerStyle.GetHorizontalFrameSize()))
+
+	lines := []string{line.Render(m.input.View())}
+	switch {
+	case m.searchedQuery == "":
+		lines = append(lines, faintStyle.Render("Press enter to search"))
+	case len(m.identities) == 0:
+		lines = append(lines, faintStyle.Render(fmt.Sprintf(`Nobody matches "%s"`, m.searchedQuery)))
+	}
+
+	// Scroll the results so the selected identity stays visible.
+	start := max(0, m.cursor-results+1)
+	end := min(len(m.identities), start+results)
+	for i := start; i < end; i++ {
+		identity := m.identities[i]
+		text := identity.DisplayName
+		if identity.UniqueName != "" {
+			text += faintStyle.Render(" <" + identity.UniqueName + ">")
+		}
+		if i == m.cursor {
+			text = selectedStyle.Render("> ") + text
+		} else {
+			text = "  " + text
+		}
+		lines = append(lines, line.Render(text))
+	}
+	for len(lines) < 1+results {
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, line.Render(faintStyle.Render(help)))
+
+	return pickerStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
@@ -0,0 +1,410 @@
+package keys
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+)
+
+type KeyMap struct {
+	Up            key.Binding
+	Down          key.Binding
+	PageUp        key.Binding
+	PageDown      key.Binding
+	FirstLine     key.Binding
+	LastLine      key.Binding
+	NextSection   key.Binding
+	PrevSection   key.Binding
+	Refresh       key.Binding
+	Filter        key.Binding
+	Sort          key.Binding
+	TogglePreview key.Binding
+	PreviewUp     key.Binding
+	PreviewDown   key.Binding
+	Help          key.Binding
+	Quit          key.Binding
+}
+
+var Keys = KeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "k"),
+		key.WithHelp("↑/k", "up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j"),
+		key.WithHelp("↓/j", "down"),
+	),
+	PageUp: key.NewBinding(
+		key.WithKeys("pgup", "ctrl+u"),
+		key.WithHelp("pgup/ctrl+u", "page up"),
+	),
+	PageDown: key.NewBinding(
+		key.WithKeys("pgdown", "ctrl+d"),
+		key.WithHelp("pgdn/ctrl+d", "page down"),
+	),
+	FirstLine: key.NewBinding(
+		key.WithKeys("home", "g"),
+		key.WithHelp("g/home", "first line"),
+	),
+	LastLine: key.NewBinding(
+		key.WithKeys("end", "G"),
+		key.WithHelp("G/end", "last line"),
+	),
+	NextSection: key.NewBinding(
+		key.WithKeys("tab", "l", "right"),
+		key.WithHelp("tab/l", "next section"),
+	),
+	PrevSection: key.NewBinding(
+		key.WithKeys("shift+tab", "h", "left"),
+		key.WithHelp("shift+tab/h", "previous section"),
+	),
+	Refresh: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "refresh"),
+	),
+	Filter: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "filter"),
+	),
+	Sort: key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "sort"),
+	),
+	TogglePreview: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "toggle preview"),
+	),
+	PreviewUp: key.NewBinding(
+		key.WithKeys("K"),
+		key.WithHelp("K", "scroll preview up"),
+	),
+	PreviewDown: key.NewBinding(
+		key.WithKeys("J"),
+		key.WithHelp("J", "scroll preview down"),
+	),
+	Help: key.NewBinding(
+		key.WithKeys("?"),
+		key.WithHelp("?", "help"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "esc", "ctrl+c"),
+		key.WithHelp("q", "quit"),
+	),
+}
+
+type PrKeyMap struct {
+	OpenInBrowser          key.Binding
+	Approve                key.Binding
+	ApproveWithSuggestions key.Binding
+	WaitForAuthor          key.Binding
+	Reject                 key.Binding
+	ResetVote              key.Binding
+	Complete               key.Binding
+	AutoComplete           key.Binding
+	AddReviewer            key.Binding
+	Threads                key.Binding
+	Diff                   key.Binding
+	Checkout               key.Binding
+	OpenWorkItem           key.Binding
+	RemoveReviewer         key.Binding
+	Abandon                key.Binding
+	ToggleDraft            key.Binding
+}
+
+var PrKeys = PrKeyMap{
+	OpenInBrowser: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "open in browser"),
+	),
+	Approve: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "approve"),
+	),
+	ApproveWithSuggestions: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "approve with suggestions"),
+	),
+	WaitForAuthor: key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "wait for author"),
+	),
+	Reject: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "reject"),
+	),
+	ResetVote: key.NewBinding(
+		key.WithKeys("z"),
+		key.WithHelp("z", "reset vote"),
+	),
+	Complete: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "complete (merge)"),
+	),
+	AutoComplete: key.NewBinding(
+		key.WithKeys("A"),
+		key.WithHelp("A", "set/cancel auto-complete"),
+	),
+	Threads: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "comments"),
+	),
+	Diff: key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "diff"),
+	),
+	Checkout: key.NewBinding(
+		key.WithKeys("C"),
+		key.WithHelp("C", "checkout"),
+	),
+	OpenWorkItem: key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i", "open work item"),
+	),
+	AddReviewer: key.NewBinding(
+		key.WithKeys("+"),
+		key.WithHelp("+", "add reviewer"),
+	),
+	RemoveReviewer: key.NewBinding(
+		key.WithKeys("-"),
+		key.WithHelp("-", "remove reviewer"),
+	),
+	Abandon: key.NewBinding(
+		key.WithKeys("X"),
+		key.WithHelp("X", "abandon/reactivate"),
+	),
+	ToggleDraft: key.NewBinding(
+		key.WithKeys("D"),
+		key.WithHelp("D", "draft/publish"),
+	),
+}
+
+type WorkItemKeyMap struct {
+	OpenInBrowser key.Binding
+	SetState      key.Binding
+	Assign        key.Binding
+}
+
+var WorkItemKeys = WorkItemKeyMap{
+	OpenInBrowser: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "open in browser"),
+	),
+	SetState: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "set state"),
+	),
+	Assign: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "assign"),
+	),
+}
+
+func (k WorkItemKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{Keys.Up, Keys.Down, Keys.NextSection, k.OpenInBrowser, k.SetState, k.Assign, Keys.Refresh, Keys.Help, Keys.Quit}
+}
+
+func (k WorkItemKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{Keys.Up, Keys.Down, Keys.PageUp, Keys.PageDown, Keys.FirstLine, Keys.LastLine},
+		{Keys.NextSection, Keys.PrevSection, Keys.Refresh},
+		{k.OpenInBrowser, k.SetState, k.Assign},
+		{Keys.Help, Keys.Quit},
+	}
+}
+
+type PromptKeyMap struct {
+	Confirm key.Binding
+	Cancel  key.Binding
+}
+
+var PromptKeys = PromptKeyMap{
+	Confirm: key.NewBinding(
+		key.WithKeys("y", "enter"),
+		key.WithHelp("y/enter", "confirm"),
+	),
+	Cancel: key.NewBinding(
+		key.WithKeys("n", "esc", "ctrl+c"),
+		key.WithHelp("n/esc", "cancel"),
+	),
+}
+
+type ThreadKeyMap struct {
+	Reply      key.Binding
+	NewComment key.Binding
+	SetStatus  key.Binding
+	Close      key.Binding
+}
+
+var ThreadKeys = ThreadKeyMap{
+	Reply: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "reply"),
+	),
+	NewComment: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "new comment"),
+	),
+	SetStatus: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "set status"),
+	),
+	Close: key.NewBinding(
+		key.WithKeys("esc", "q"),
+		key.WithHelp("esc/q", "close"),
+	),
+}
+
+func (k ThreadKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		Keys.Up,
+		Keys.Down,
+		k.Reply,
+		k.NewComment,
+		k.SetStatus,
+		k.Close,
+	}
+}
+
+func (k ThreadKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+type DiffKeyMap struct {
+	NextFile   key.Binding
+	PrevFile   key.Binding
+	NextHunk   key.Binding
+	PrevHunk   key.Binding
+	Iterations key.Binding
+	Close      key.Binding
+}
+
+var DiffKeys = DiffKeyMap{
+	NextFile: key.NewBinding(
+		key.WithKeys("]", "tab"),
+		key.WithHelp("]", "next file"),
+	),
+	PrevFile: key.NewBinding(
+		key.WithKeys("[", "shift+tab"),
+		key.WithHelp("[", "previous file"),
+	),
+	NextHunk: key.NewBinding(
+		key.WithKeys("n", "}"),
+		key.WithHelp("n", "next hunk"),
+	),
+	PrevHunk: key.NewBinding(
+		key.WithKeys("N", "{"),
+		key.WithHelp("N", "previous hunk"),
+	),
+	Iterations: key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i", "compare iterations"),
+	),
+	Close: key.NewBinding(
+		key.WithKeys("esc", "q"),
+		key.WithHelp("esc/q", "close"),
+	),
+}
+
+func (k DiffKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		Keys.Up,
+		Keys.Down,
+		Keys.PageDown,
+		k.NextFile,
+		k.PrevFile,
+		k.NextHunk,
+		k.PrevHunk,
+		k.Iterations,
+		k.Close,
+	}
+}
+
+func (k DiffKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+// InputKeyMap holds the keys of text inputs. Letters are typed into the
+// input, so the bindings avoid them.
+type InputKeyMap struct {
+	Up             key.Binding
+	Down           key.Binding
+	Submit         key.Binding
+	ToggleRequired key.Binding
+	Send           key.Binding
+	Cancel         key.Binding
+}
+
+var InputKeys = InputKeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "ctrl+p"),
+		key.WithHelp("↑", "up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "ctrl+n"),
+		key.WithHelp("↓", "down"),
+	),
+	Submit: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "search/add"),
+	),
+	ToggleRequired: key.NewBinding(
+		key.WithKeys("ctrl+r"),
+		key.WithHelp("ctrl+r", "required/optional"),
+	),
+	Send: key.NewBinding(
+		key.WithKeys("ctrl+s"),
+		key.WithHelp("ctrl+s", "send"),
+	),
+	Cancel: key.NewBinding(
+		key.WithKeys("esc", "ctrl+c"),
+		key.WithHelp("esc", "cancel"),
+	),
+}
+
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.NextSection, k.TogglePreview, PrKeys.OpenInBrowser, k.Refresh, k.Help, k.Quit}
+}
+
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.PageUp, k.PageDown, k.FirstLine, k.LastLine},
+		{k.NextSection, k.PrevSection, k.Refresh, k.Filter, k.Sort},
+		{k.TogglePreview, k.PreviewUp, k.PreviewDown},
+		PrKeys.FullHelp(),
+		{k.Help, k.Quit},
+	}
+}
+
+func (k PrKeyMap) FullHelp() []key.Binding {
+	return []key.Binding{
+		k.OpenInBrowser,
+		k.Approve,
+		k.ApproveWithSuggestions,
+		k.WaitForAuthor,
+		k.Reject,
+		k.ResetVote,
+		k.Complete,
+		k.AutoComplete,
+		k.AddReviewer,
+		k.RemoveReviewer,
+		k.Threads,
+		k.Diff,
+		k.Checkout,
+		k.OpenWorkItem,
+		k.Abandon,
+		k.ToggleDraft,
+	}
+}
+
+// TableKeyMap maps the navigation keys onto the table component.
+func TableKeyMap() table.KeyMap {
+	return table.KeyMap{
+		LineUp:       Keys.Up,
+		LineDown:     Keys.Down,
+		PageUp:       Keys.PageUp,
+		PageDown:     Keys.PageDown,
+		HalfPageUp:   key.NewBinding(key.WithDisabled()),
+		HalfPageDown: key.NewBinding(key.WithDisabled()),
+		GotoTop:      Keys.FirstLine,
+		GotoBottom:   Keys.LastLine,
+	}
+}
@@ -0,0 +1,68 @@
+package markdown
+
+import (
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/ansi"
+	"github.com/charmbracelet/log"
+	"sync"
+)
+
+var (
+	markdownStyle = glamour.DarkStyleConfig
+	renderers     = map[int]*glamour.TermRenderer{}
+	renderersLock sync.Mutex
+)
+
+// InitializeMarkdownStyle picks the markdown style matching the terminal
+// background. It has to be called before the first markdown is rendered.
+func InitializeMarkdownStyle(hasDarkBackground bool) {
+	if hasDarkBackground {
+		markdownStyle = glamour.DarkStyleConfig
+	} else {
+		markdownStyle = glamour.LightStyleConfig
+	}
+}
+
+func getStyle() ansi.StyleConfig {
+	style := markdownStyle
+	// The pane already has a border, glamour's document margin wastes space.
+	var margin uint = 0
+	style.Document.Margin = &margin
+	return style
+}
+
+func getRenderer(width int) (*glamour.TermRenderer, error) {
+	renderersLock.Lock()
+	defer renderersLock.Unlock()
+
+	if renderer, ok := renderers[width]; ok {
+		return renderer, nil
+	}
+
+	renderer, err := glamour.NewTermRenderer(
+		glamour.WithStyles(getStyle()),
+		glamour.WithWordWrap(width),
+	)
+	if err != nil {
+		return nil, err
+	}
+	renderers[width] = renderer
+	return renderer, nil
+}
+
+// Render renders markdown wrapped at width. The input is returned as is if
+// it cannot be rendered.
+func Render(markdown string, width int) string {
+	renderer, err := getRenderer(width)
+	if err != nil {
+		log.Error("Failed creating markdown renderer", "err", err)
+		return markdown
+	}
+
+	rendered, err := renderer.Render(markdown)
+	if err != nil {
+		log.Error("Failed rendering markdown", "err", err)
+		return markdown
+	}
+	return rendered
+}
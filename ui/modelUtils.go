@@ -3,10 +3,22 @@ package ui
 import "azdo-dash/ui/section"
 
 func (m *Model) getCurrSection() section.Section {
-	section := m.getCurrentViewSections()
-	return section
+	sections := m.getCurrentViewSections()
+	if m.currSection < 0 || m.currSection >= len(sections) {
+		return nil
+	}
+	return sections[m.currSection]
 }
 
-func (m *Model) getCurrentViewSections() section.Section {
-	return m.prSection
+func (m *Model) getCurrentViewSections() []section.Section {
+	return m.sections
+}
+
+// setCurrSection selects the section at index, wrapping around at both ends.
+func (m *Model) setCurrSection(index int) {
+	sections := m.getCurrentViewSections()
+	if len(sections) == 0 {
+		return
+	}
+	m.currSection = (index + len(sections)) % len(sections)
 }
@@ -0,0 +1,365 @@
+package prssection
+
+import (
+	"azdo-dash/context"
+	"azdo-dash/data"
+	"azdo-dash/ui/section"
+	gocontext "context"
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/cli/browser"
+	"slices"
+	"time"
+)
+
+func (m *Model) openInBrowser() tea.Cmd {
+	pr := m.GetCurrPr()
+	if pr == nil {
+		return nil
+	}
+
+	url := m.Ctx.Client.PullRequestWebURL(*pr)
+	task := context.Task{
+		Id:           fmt.Sprintf("open_pr_%d_%s", pr.ID, time.Now().String()),
+		StartText:    fmt.Sprintf("Opening PR #%d in the browser", pr.ID),
+		FinishedText: fmt.Sprintf("PR #%d has been opened in the browser", pr.ID),
+	}
+
+	return m.RunTask(task, func() (tea.Msg, error) {
+		return nil, browser.OpenURL(url)
+	})
+}
+
+var voteTexts = map[int]struct {
+	start    string
+	finished string
+}{
+	data.VoteApproved:                {"Approving", "approved"},
+	data.VoteApprovedWithSuggestions: {"Approving with suggestions", "approved with suggestions"},
+	data.VoteWaitingForAuthor:        {"Waiting for the author of", "set to wait for the author"},
+	data.VoteRejected:                {"Rejecting", "rejected"},
+	data.VoteNone:                    {"Resetting the vote on", "no longer voted on"},
+}
+
+func (m *Model) vote(vote int) tea.Cmd {
+	pr := m.GetCurrPr()
+	if pr == nil {
+		return nil
+	}
+
+	client := m.Ctx.Client
+	user := *m.Ctx.User
+	texts := voteTexts[vote]
+	task := context.Task{
+		Id:           fmt.Sprintf("vote_pr_%d_%s", pr.ID, time.Now().String()),
+		StartText:    fmt.Sprintf("%s PR #%d", texts.start, pr.ID),
+		FinishedText: fmt.Sprintf("PR #%d has been %s", pr.ID, texts.finished),
+	}
+
+	visits := m.Ctx.Visits
+	target := *pr
+	return m.runPrTask(target, task, func(ctx gocontext.Context) error {
+		if err := client.Vote(ctx, target, user.ID, vote); err != nil {
+			return err
+		}
+		// The changes pushed after the vote are what the user has not
+		// reviewed yet. The iterations are only known once the pull
+		// request was previewed.
+		if !target.HasDetails {
+			target.Iterations, _ = client.FetchIterations(ctx, target)
+		}
+		if latest := target.LatestIteration(); latest != 0 {
+			if err := visits.RecordVote(target, latest); err != nil {
+				log.Error("Failed recording the vote on a pull request", "id", target.ID, "err", err)
+			}
+		}
+		return nil
+	})
+}
+
+// runPrTask runs action on a pull request as a task and fetches the pull
+// request again afterwards, so its row reflects the change.
+func (m *Model) runPrTask(
+	pr data.PullRequestData,
+	task context.Task,
+	action func(ctx gocontext.Context) error,
+) tea.Cmd {
+	client := m.Ctx.Client
+	user := *m.Ctx.User
+
+	return m.RunTask(task, func() (tea.Msg, error) {
+		ctx := gocontext.Background()
+		if err := action(ctx); err != nil {
+			return nil, err
+		}
+
+		updatedPr, err := client.FetchPullRequest(ctx, pr.ProjectID, pr.RepositoryID, pr.ID, user)
+		if err != nil {
+			return nil, fmt.Errorf("refreshing PR #%d: %w", pr.ID, err)
+		}
+		return pullRequestFetchedMsg{Pr: updatedPr}, nil
+	})
+}
+
+const (
+	completeAction           = "complete"
+	autoCompleteAction       = "auto_complete"
+	cancelAutoCompleteAction = "cancel_auto_complete"
+	abandonAction            = "abandon"
+	reactivateAction         = "reactivate"
+	toggleDraftAction        = "toggle_draft"
+)
+
+const (
+	mergeStrategyOption      = "strategy"
+	deleteSourceBranchOption = "delete branch"
+	transitionWorkItemOption = "complete work items"
+)
+
+// mergeStrategies are offered in the order Azure DevOps lists them.
+var mergeStrategies = []string{
+	data.MergeStrategyNoFastForward,
+	data.MergeStrategySquash,
+	data.MergeStrategyRebase,
+	data.MergeStrategyRebaseMerge,
+}
+
+func (m *Model) promptComplete() {
+	pr := m.GetCurrPr()
+	if pr == nil {
+		return
+	}
+
+	m.showPrPrompt(
+		*pr,
+		completeAction,
+		fmt.Sprintf("Complete PR #%d?", pr.ID),
+		completionPromptOptions(*pr)...,
+	)
+}
+
+// completionPromptOptions preselects the completion options of pr, which
+// keep the source branch and the work items unless the author chose
+// otherwise. Neither can be undone from the dashboard.
+func completionPromptOptions(pr data.PullRequestData) []section.PromptOption {
+	options := pr.CompletionOptions
+	return []section.PromptOption{
+		{
+			Key:      "s",
+			Label:    mergeStrategyOption,
+			Choices:  mergeStrategies,
+			Selected: max(0, slices.Index(mergeStrategies, options.MergeStrategy)),
+		},
+		{
+			Key:      "d",
+			Label:    deleteSourceBranchOption,
+			Choices:  section.YesNo,
+			Selected: yesNoIndex(options.DeleteSourceBranch),
+		},
+		{
+			Key:      "t",
+			Label:    transitionWorkItemOption,
+			Choices:  section.YesNo,
+			Selected: yesNoIndex(options.TransitionWorkItems),
+		},
+	}
+}
+
+func yesNoIndex(value bool) int {
+	if value {
+		return slices.Index(section.YesNo, "yes")
+	}
+	return slices.Index(section.YesNo, "no")
+}
+
+// completionOptions returns the completion options chosen in the prompt.
+func (m *Model) completionOptions() data.CompletionOptions {
+	return data.CompletionOptions{
+		MergeStrategy:       m.GetPromptOption(mergeStrategyOption),
+		DeleteSourceBranch:  m.GetPromptOption(deleteSourceBranchOption) == "yes",
+		TransitionWorkItems: m.GetPromptOption(transitionWorkItemOption) == "yes",
+	}
+}
+
+func (m *Model) complete() tea.Cmd {
+	pr := m.promptPr
+
+	client := m.Ctx.Client
+	options := m.completionOptions()
+	task := context.Task{
+		Id:           fmt.Sprintf("complete_pr_%d_%s", pr.ID, time.Now().String()),
+		StartText:    fmt.Sprintf("Completing PR #%d", pr.ID),
+		FinishedText: fmt.Sprintf("PR #%d has been completed", pr.ID),
+	}
+
+	return m.runPrTask(pr, task, func(ctx gocontext.Context) error {
+		return client.CompletePullRequest(ctx, pr, options)
+	})
+}
+
+func (m *Model) promptAutoComplete() {
+	pr := m.GetCurrPr()
+	if pr == nil || pr.Status != data.PullRequestStatusActive {
+		return
+	}
+
+	if pr.AutoCompleteSetBy != "" {
+		m.showPrPrompt(*pr, cancelAutoCompleteAction, fmt.Sprintf("Cancel auto-complete of PR #%d?", pr.ID))
+		return
+	}
+	m.showPrPrompt(
+		*pr,
+		autoCompleteAction,
+		fmt.Sprintf("Auto-complete PR #%d?", pr.ID),
+		completionPromptOptions(*pr)...,
+	)
+}
+
+func (m *Model) setAutoComplete() tea.Cmd {
+	pr := m.promptPr
+
+	client := m.Ctx.Client
+	user := *m.Ctx.User
+	options := m.completionOptions()
+	task := context.Task{
+		Id:           fmt.Sprintf("auto_complete_pr_%d_%s", pr.ID, time.Now().String()),
+		StartText:    fmt.Sprintf("Setting auto-complete on PR #%d", pr.ID),
+		FinishedText: fmt.Sprintf("PR #%d will complete once its policies pass", pr.ID),
+	}
+
+	return m.runPrTask(pr, task, func(ctx gocontext.Context) error {
+		return client.SetAutoComplete(ctx, pr, user.ID, options)
+	})
+}
+
+func (m *Model) cancelAutoComplete() tea.Cmd {
+	pr := m.promptPr
+
+	client := m.Ctx.Client
+	task := context.Task{
+		Id:           fmt.Sprintf("cancel_auto_complete_pr_%d_%s", pr.ID, time.Now().String()),
+		StartText:    fmt.Sprintf("Cancelling auto-complete of PR #%d", pr.ID),
+		FinishedText: fmt.Sprintf("PR #%d will no longer complete automatically", pr.ID),
+	}
+
+	return m.runPrTask(pr, task, func(ctx gocontext.Context) error {
+		return client.CancelAutoComplete(ctx, pr)
+	})
+}
+
+func (m *Model) promptAbandon() {
+	pr := m.GetCurrPr()
+	if pr == nil {
+		return
+	}
+
+	switch pr.Status {
+	case data.PullRequestStatusActive:
+		m.showPrPrompt(*pr, abandonAction, fmt.Sprintf("Abandon PR #%d?", pr.ID))
+	case data.PullRequestStatusAbandoned:
+		m.showPrPrompt(*pr, reactivateAction, fmt.Sprintf("Reactivate PR #%d?", pr.ID))
+	}
+}
+
+func (m *Model) abandon() tea.Cmd {
+	pr := m.promptPr
+
+	client := m.Ctx.Client
+	task := context.Task{
+		Id:           fmt.Sprintf("abandon_pr_%d_%s", pr.ID, time.Now().String()),
+		StartText:    fmt.Sprintf("Abandoning PR #%d", pr.ID),
+		FinishedText: fmt.Sprintf("PR #%d has been abandoned", pr.ID),
+	}
+
+	return m.runPrTask(pr, task, func(ctx gocontext.Context) error {
+		return client.AbandonPullRequest(ctx, pr)
+	})
+}
+
+func (m *Model) reactivate() tea.Cmd {
+	pr := m.promptPr
+
+	client := m.Ctx.Client
+	task := context.Task{
+		Id:           fmt.Sprintf("reactivate_pr_%d_%s", pr.ID, time.Now().String()),
+		StartText:    fmt.Sprintf("Reactivating PR #%d", pr.ID),
+		FinishedText: fmt.Sprintf("PR #%d has been reactivated", pr.ID),
+	}
+
+	return m.runPrTask(pr, task, func(ctx gocontext.Context) error {
+		return client.ReactivatePullRequest(ctx, pr)
+	})
+}
+
+func (m *Model) promptToggleDraft() {
+	pr := m.GetCurrPr()
+	if pr == nil || pr.Status != data.PullRequestStatusActive {
+		return
+	}
+
+	text := fmt.Sprintf("Mark PR #%d as draft?", pr.ID)
+	if pr.IsDraft {
+		text = fmt.Sprintf("Publish PR #%d for review?", pr.ID)
+	}
+	m.showPrPrompt(*pr, toggleDraftAction, text)
+}
+
+func (m *Model) toggleDraft() tea.Cmd {
+	pr := m.promptPr
+
+	client := m.Ctx.Client
+	isDraft := !pr.IsDraft
+	task := context.Task{
+		Id:           fmt.Sprintf("draft_pr_%d_%s", pr.ID, time.Now().String()),
+		StartText:    fmt.Sprintf("Marking PR #%d as draft", pr.ID),
+		FinishedText: fmt.Sprintf("PR #%d has been marked as draft", pr.ID),
+	}
+	if !isDraft {
+		task.StartText = fmt.Sprintf("Publishing PR #%d", pr.ID)
+		task.FinishedText = fmt.Sprintf("PR #%d has been published", pr.ID)
+	}
+
+	return m.runPrTask(pr, task, func(ctx gocontext.Context) error {
+		return client.SetDraft(ctx, pr, isDraft)
+	})
+}
+
+// showPrPrompt asks the user to confirm action on pr. The action runs on pr
+// even if the rows change while the prompt is shown, e.g. when the next page
+// is loaded.
+func (m *Model) showPrPrompt(pr data.PullRequestData, action string, text string, options ...section.PromptOption) {
+	m.promptPr = pr
+	m.ShowPrompt(action, text, options...)
+}
+
+// onPromptConfirmed runs the action the user confirmed in the prompt.
+func (m *Model) onPromptConfirmed() tea.Cmd {
+	switch m.PromptConfirmationAction {
+	case completeAction:
+		return m.complete()
+	case autoCompleteAction:
+		return m.setAutoComplete()
+	case cancelAutoCompleteAction:
+		return m.cancelAutoComplete()
+	case abandonAction:
+		return m.abandon()
+	case reactivateAction:
+		return m.reactivate()
+	case toggleDraftAction:
+		return m.toggleDraft()
+	case removeReviewerAction:
+		return m.removeReviewer()
+	case threadStatusAction:
+		return m.setThreadStatus()
+	case compareIterationsAction:
+		return m.compareIterations()
+	case checkoutAction:
+		return m.checkout()
+	case openWorkItemAction:
+		return m.openSelectedWorkItem()
+	case sortAction:
+		m.sort()
+	}
+	return nil
+}
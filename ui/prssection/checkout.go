@@ -0,0 +1,130 @@
+package prssection
+
+import (
+	"azdo-dash/config"
+	"azdo-dash/constants"
+	"azdo-dash/context"
+	"azdo-dash/data"
+	"azdo-dash/git"
+	"azdo-dash/ui/section"
+	gocontext "context"
+	"errors"
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const checkoutAction = "checkout"
+
+const worktreeOption = "worktree"
+
+// defaultRemote is fetched from if a configured clone has no remote pointing
+// to the repository, e.g. because it was cloned from a mirror.
+const defaultRemote = "origin"
+
+func (m *Model) promptCheckout() {
+	pr := m.GetCurrPr()
+	if pr == nil {
+		return
+	}
+
+	worktree := 1
+	if m.Ctx.Config.Checkout.Worktree {
+		worktree = 0
+	}
+	m.showPrPrompt(
+		*pr,
+		checkoutAction,
+		fmt.Sprintf("Check out %s of %s?", removePrefix(pr.SourceBranch), pr.RepositoryName),
+		section.PromptOption{Key: "w", Label: worktreeOption, Choices: section.YesNo, Selected: worktree},
+	)
+}
+
+// checkout fetches the source branch of the pull request of the prompt into
+// its local clone and checks it out, in a worktree next to the clone if
+// chosen so in the prompt.
+func (m *Model) checkout() tea.Cmd {
+	pr := m.promptPr
+
+	cfg := m.Ctx.Config.Checkout
+	remoteURL := m.Ctx.Client.RepositoryRemoteURL(pr)
+	branch := removePrefix(pr.SourceBranch)
+	useWorktree := m.GetPromptOption(worktreeOption) == "yes"
+	task := context.Task{
+		Id:           fmt.Sprintf("checkout_pr_%d_%s", pr.ID, time.Now().String()),
+		StartText:    fmt.Sprintf("Checking out %s", branch),
+		FinishedText: fmt.Sprintf("%s has been checked out", branch),
+	}
+
+	return m.RunTask(task, func() (tea.Msg, error) {
+		ctx := gocontext.Background()
+		dir, err := findClone(ctx, cfg, pr, remoteURL)
+		if err != nil {
+			return nil, err
+		}
+
+		remote, err := git.FindRemote(ctx, dir, remoteURL)
+		if err != nil {
+			return nil, err
+		}
+		if remote == "" {
+			remote = defaultRemote
+		}
+
+		if useWorktree {
+			worktree, err := git.CheckoutWorktree(ctx, dir, remote, branch, dir+".worktrees")
+			if err != nil {
+				return nil, err
+			}
+			return constants.TaskFinishedTextMsg(fmt.Sprintf("%s has been checked out in %s", branch, worktree)), nil
+		}
+
+		if err := git.Checkout(ctx, dir, remote, branch); err != nil {
+			return nil, err
+		}
+		return constants.TaskFinishedTextMsg(fmt.Sprintf("%s has been checked out in %s", branch, dir)), nil
+	})
+}
+
+// findClone returns the local clone of the repository of a pull request. A
+// configured path wins over looking for the clone in the working directory
+// and the search directories.
+func findClone(ctx gocontext.Context, cfg config.CheckoutConfig, pr data.PullRequestData, remoteURL string) (string, error) {
+	for _, repo := range []string{pr.RepositoryID, pr.RepositoryName} {
+		if path, ok := cfg.RepoPaths[repo]; ok {
+			return expandHome(path), nil
+		}
+	}
+
+	var dirs []string
+	if wd, err := os.Getwd(); err == nil {
+		dirs = append(dirs, wd)
+	}
+	for _, dir := range cfg.SearchDirs {
+		dirs = append(dirs, expandHome(dir))
+	}
+
+	dir, err := git.FindClone(ctx, dirs, remoteURL)
+	if errors.Is(err, git.ErrCloneNotFound) {
+		return "", fmt.Errorf(
+			"no local clone of %s found, add it to checkout.repo_paths or checkout.search_dirs",
+			pr.RepositoryName,
+		)
+	}
+	return dir, err
+}
+
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
@@ -0,0 +1,312 @@
+package prssection
+
+import (
+	"azdo-dash/data"
+	"azdo-dash/ui/section"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"strconv"
+	"strings"
+)
+
+// column describes a column of the pull requests table. Columns are at least
+// width wide and share the remaining width of the screen by their grow
+// factor, narrow screens hide the columns of the highest priority first.
+// Rows are filtered and sorted by text, or by number if it is set. Columns
+// without text use their value without colors.
+type column struct {
+	title    string
+	width    int
+	grow     int
+	priority int
+	value    func(pr data.PullRequestData) string
+	text     func(pr data.PullRequestData) string
+	number   func(pr data.PullRequestData) int
+}
+
+var columns = []column{
+	{title: "Repository", width: 12, grow: 1, priority: 7, value: func(pr data.PullRequestData) string {
+		return pr.RepositoryName
+	}},
+	{title: "Title", width: 20, grow: 3, priority: 0, value: func(pr data.PullRequestData) string {
+		return pr.Title
+	}},
+	{title: "CreatedBy", width: 12, grow: 1, priority: 3, value: func(pr data.PullRequestData) string {
+		return pr.CreatedBy
+	}},
+	{title: "Status", width: 6, priority: 1,
+		value: func(pr data.PullRequestData) string {
+			return formatStatus(pr)
+		},
+		text: func(pr data.PullRequestData) string {
+			if pr.IsDraft && pr.Status == data.PullRequestStatusActive {
+				return "draft"
+			}
+			return pr.Status
+		},
+	},
+	{title: "Required", width: 8, priority: 6,
+		value: func(pr data.PullRequestData) string {
+			return formatBool(pr.IsRequiredReviewer)
+		},
+		text: func(pr data.PullRequestData) string {
+			return boolText(pr.IsRequiredReviewer)
+		},
+	},
+	{title: "Vote", width: 4, priority: 2,
+		value: func(pr data.PullRequestData) string {
+			return formatVote(pr.Vote)
+		},
+		number: func(pr data.PullRequestData) int {
+			return pr.Vote
+		},
+	},
+	{title: "Auto", width: 4, priority: 9,
+		value: func(pr data.PullRequestData) string {
+			return formatAutoComplete(pr.AutoCompleteSetBy)
+		},
+		text: func(pr data.PullRequestData) string {
+			return pr.AutoCompleteSetBy
+		},
+	},
+	{title: "Comments", width: 8, priority: 5,
+		value: func(pr data.PullRequestData) string {
+			if !pr.HasIndicators {
+				return pendingIndicator
+			}
+			return formatUnresolvedThreads(pr.UnresolvedThreads)
+		},
+		number: func(pr data.PullRequestData) int {
+			return pr.UnresolvedThreads
+		},
+	},
+	{title: "Merge", width: 5, priority: 8,
+		value: func(pr data.PullRequestData) string {
+			return formatMergeStatus(pr.MergeStatus)
+		},
+		text: func(pr data.PullRequestData) string {
+			return pr.MergeStatus
+		},
+	},
+	{title: "Checks", width: 6, priority: 4,
+		value: func(pr data.PullRequestData) string {
+			if !pr.HasIndicators {
+				return pendingIndicator
+			}
+			return formatChecksState(pr.ChecksState)
+		},
+		text: func(pr data.PullRequestData) string {
+			return pr.ChecksState
+		},
+	},
+	{title: "Items", width: 5, priority: 10,
+		value: func(pr data.PullRequestData) string {
+			if !pr.HasIndicators {
+				return pendingIndicator
+			}
+			return formatWorkItems(pr.WorkItems)
+		},
+		number: func(pr data.PullRequestData) int {
+			if pr.WorkItems == nil {
+				return -1
+			}
+			return len(pr.WorkItems)
+		},
+	},
+	{title: "SourceBranch", width: 12, grow: 1, priority: 11, value: func(pr data.PullRequestData) string {
+		return removePrefix(pr.SourceBranch)
+	}},
+	{title: "IsDraft", width: 7, priority: 12,
+		value: func(pr data.PullRequestData) string {
+			return formatBool(pr.IsDraft)
+		},
+		text: func(pr data.PullRequestData) string {
+			return boolText(pr.IsDraft)
+		},
+	},
+}
+
+var (
+	statusActive            = lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Render("●")
+	statusCompleted         = lipgloss.NewStyle().Foreground(lipgloss.Color("6")).Render("●")
+	statusDraft             = lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render("●")
+	statusAbandoned         = lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render("●")
+	autoComplete            = lipgloss.NewStyle().Foreground(lipgloss.Color("6")).Render("⚡")
+	unresolvedThreads       = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	unknownThreads          = lipgloss.NewStyle().Faint(true).Render("?")
+	mergeSucceeded          = lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Render("✓")
+	mergeConflicts          = lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render("✗")
+	mergeRejected           = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("⊘")
+	mergeFailure            = lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render("!")
+	mergeQueued             = lipgloss.NewStyle().Faint(true).Render("…")
+	checksPassed            = lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Render("✓")
+	checksFailed            = lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render("✗")
+	checksPending           = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("●")
+	checksMissing           = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("!")
+	checksUnknown           = lipgloss.NewStyle().Faint(true).Render("?")
+	workItems               = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+	noWorkItems             = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("!")
+	unknownWorkItems        = lipgloss.NewStyle().Faint(true).Render("?")
+	pendingIndicator        = lipgloss.NewStyle().Faint(true).Render("…")
+	checkMark               = lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Render("✓")
+	crossMark               = lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render("✗")
+	noVote                  = lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render("")
+	approved                = lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Render("✓")
+	approvedWithSuggestions = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("✓✍")
+	waitForAuthor           = lipgloss.NewStyle().Foreground(lipgloss.Color("4")).Render("⌛")
+	rejected                = lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render("✗")
+)
+
+func formatAutoComplete(setBy string) string {
+	if setBy == "" {
+		return ""
+	}
+	return autoComplete
+}
+
+func formatUnresolvedThreads(count int) string {
+	switch {
+	case count < 0:
+		return unknownThreads
+	case count == 0:
+		return ""
+	default:
+		return unresolvedThreads.Render(strconv.Itoa(count))
+	}
+}
+
+func formatMergeStatus(status string) string {
+	switch status {
+	case data.MergeStatusSucceeded:
+		return mergeSucceeded
+	case data.MergeStatusConflicts:
+		return mergeConflicts
+	case data.MergeStatusRejectedByPolicy:
+		return mergeRejected
+	case data.MergeStatusFailure:
+		return mergeFailure
+	case data.MergeStatusQueued:
+		return mergeQueued
+	default:
+		return ""
+	}
+}
+
+func formatChecksState(state string) string {
+	switch state {
+	case data.CheckStatePassed:
+		return checksPassed
+	case data.CheckStateFailed:
+		return checksFailed
+	case data.CheckStatePending:
+		return checksPending
+	case data.CheckStateMissing:
+		return checksMissing
+	case data.CheckStateUnknown:
+		return checksUnknown
+	default:
+		return ""
+	}
+}
+
+// formatWorkItems counts the linked work items. Pull requests without any
+// are marked, as they are expected to have one.
+func formatWorkItems(items []data.WorkItem) string {
+	switch {
+	case items == nil:
+		return unknownWorkItems
+	case len(items) == 0:
+		return noWorkItems
+	default:
+		return workItems.Render(strconv.Itoa(len(items)))
+	}
+}
+
+func formatStatus(pr data.PullRequestData) string {
+	switch pr.Status {
+	case data.PullRequestStatusActive:
+		if pr.IsDraft {
+			return statusDraft
+		}
+		return statusActive
+	case data.PullRequestStatusCompleted:
+		return statusCompleted
+	case data.PullRequestStatusAbandoned:
+		return statusAbandoned
+	case "draft":
+		return statusDraft
+	default:
+		return pr.Status
+	}
+}
+
+func formatVote(vote int) string {
+	switch vote {
+	case data.VoteNone:
+		return noVote
+	case data.VoteApproved:
+		return approved
+	case data.VoteApprovedWithSuggestions:
+		return approvedWithSuggestions
+	case data.VoteWaitingForAuthor:
+		return waitForAuthor
+	case data.VoteRejected:
+		return rejected
+	default:
+		return noVote
+	}
+}
+
+func formatBool(value bool) string {
+	if value {
+		return checkMark
+	}
+	return crossMark
+}
+
+func boolText(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
+func removePrefix(refName string) string {
+	return strings.TrimPrefix(refName, "refs/heads/")
+}
+
+// tableColumns returns the columns that fit in width and the indices of
+// them in columns.
+func tableColumns(width int) ([]table.Column, []int) {
+	layouts := make([]section.ColumnLayout, 0, len(columns))
+	for _, col := range columns {
+		layouts = append(layouts, section.ColumnLayout{
+			Title:    col.title,
+			Width:    col.width,
+			Grow:     col.grow,
+			Priority: col.priority,
+		})
+	}
+	return section.TableColumns(layouts, width)
+}
+
+// tableRows renders a row per pull request with the cells of the shown
+// columns. The selected row is rendered without colors, the resets of
+// colored cells would cut its highlight short.
+func tableRows(prs []data.PullRequestData, shown []int, cursor int) []table.Row {
+	rows := make([]table.Row, 0, len(prs))
+	for i, pr := range prs {
+		row := make(table.Row, 0, len(shown))
+		for _, index := range shown {
+			value := columns[index].value(pr)
+			if i == cursor {
+				value = ansi.Strip(value)
+			}
+			row = append(row, value)
+		}
+		rows = append(rows, row)
+	}
+
+	return rows
+}
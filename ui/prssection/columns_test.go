@@ -0,0 +1,40 @@
+package prssection
+
+import (
+	"testing"
+)
+
+func TestTableColumnsFitTheWidth(t *testing.T) {
+	const cellPadding = 2
+
+	tests := []struct {
+		width       int
+		wantColumns int
+	}{
+		{40, 3},
+		{100, 9},
+		{140, len(columns)},
+	}
+
+	for _, test := range tests {
+		tableColumns, shown := tableColumns(test.width)
+
+		total := 0
+		title := 0
+		for _, col := range tableColumns {
+			total += col.Width + cellPadding
+			if col.Title == "Title" {
+				title = col.Width
+			}
+		}
+		if total > test.width {
+			t.Errorf("tableColumns(%d) is %d wide", test.width, total)
+		}
+		if len(shown) != test.wantColumns {
+			t.Errorf("tableColumns(%d) shows %d columns, want %d", test.width, len(shown), test.wantColumns)
+		}
+		if title < 20 {
+			t.Errorf("tableColumns(%d) has a Title of %d, want at least 20", test.width, title)
+		}
+	}
+}
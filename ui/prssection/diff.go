@@ -0,0 +1,466 @@
+package prssection
+
+import (
+	"azdo-dash/context"
+	"azdo-dash/data"
+	"azdo-dash/ui/diffview"
+	"azdo-dash/ui/keys"
+	"azdo-dash/ui/section"
+	gocontext "context"
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxFileListWidth is the width the list of changed files grows to at most.
+const maxFileListWidth = 40
+
+// defaultCompareTo asks fetchChanges to show the changes since the user last
+// voted or visited the pull request.
+const defaultCompareTo = -1
+
+const compareIterationsAction = "compare_iterations"
+
+const (
+	sinceIterationOption = "since"
+	upToIterationOption  = "up to"
+)
+
+// targetBranchChoice is the choice of comparing to the target branch instead
+// of an iteration.
+const targetBranchChoice = "target branch"
+
+type changesFetchedMsg struct {
+	PrId       int
+	Iterations []data.Iteration
+	Iteration  int
+	CompareTo  int
+	Changes    []data.FileChange
+}
+
+type fileDiffFetchedMsg struct {
+	PrId      int
+	Iteration int
+	CompareTo int
+	File      diffview.File
+}
+
+// diffKey identifies what the diff viewport currently shows, it is rendered
+// again whenever it changes.
+type diffKey struct {
+	path     string
+	width    int
+	isLoaded bool
+}
+
+// diffView shows the changes of a pull request in place of the table: the
+// changed files on the left and the diff of the selected one on the right.
+// Iteration is the iteration the changes are shown up to and CompareTo the
+// one they are shown since, 0 for the target branch.
+type diffView struct {
+	isOpen      bool
+	isLoading   bool
+	pr          data.PullRequestData
+	iterations  []data.Iteration
+	iteration   int
+	compareTo   int
+	changes     []data.FileChange
+	fileCursor  int
+	files       map[string]diffview.File
+	requested   map[string]bool
+	viewport    viewport.Model
+	hunkOffsets []int
+	renderedKey diffKey
+}
+
+var (
+	diffHeaderStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	fileListStyle   = lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder(), false, true, false, false).
+			BorderForeground(lipgloss.Color("8")).
+			PaddingRight(1)
+	diffPathStyle = lipgloss.NewStyle().Bold(true)
+)
+
+func (m *Model) openDiff() tea.Cmd {
+	pr := m.GetCurrPr()
+	if pr == nil {
+		return nil
+	}
+
+	m.diff = diffView{
+		isOpen:    true,
+		isLoading: true,
+		pr:        *pr,
+		files:     map[string]diffview.File{},
+		requested: map[string]bool{},
+		viewport:  viewport.New(0, 0),
+	}
+
+	return m.fetchChanges(*pr, 0, defaultCompareTo)
+}
+
+func (m *Model) closeDiff() {
+	m.diff = diffView{}
+}
+
+// fetchChanges fetches the changes of a pull request up to iteration since
+// compareTo. An iteration of 0 stands for the latest one. With
+// defaultCompareTo the changes the user has not seen yet are shown, and the
+// latest iteration is recorded as visited.
+func (m *Model) fetchChanges(pr data.PullRequestData, iteration int, compareTo int) tea.Cmd {
+	client := m.Ctx.Client
+	visits := m.Ctx.Visits
+	task := context.Task{
+		Id:           fmt.Sprintf("fetching_changes_%d_%s", pr.ID, time.Now().String()),
+		StartText:    fmt.Sprintf("Fetching the changes of PR #%d", pr.ID),
+		FinishedText: fmt.Sprintf("The changes of PR #%d have been fetched", pr.ID),
+	}
+
+	return m.RunTask(task, func() (tea.Msg, error) {
+		ctx := gocontext.Background()
+		iterations, err := client.FetchIterations(ctx, pr)
+		if err != nil {
+			return nil, fmt.Errorf("fetching the iterations of PR #%d: %w", pr.ID, err)
+		}
+		if len(iterations) == 0 {
+			return nil, fmt.Errorf("PR #%d has no iterations", pr.ID)
+		}
+		latest := iterations[len(iterations)-1].ID
+		if iteration == 0 {
+			iteration = latest
+		}
+		if compareTo == defaultCompareTo {
+			compareTo = 0
+			// Everything is unseen on the first visit, and nothing after the
+			// last one if there was no push since.
+			if lastSeen := visits.Get(pr).LastSeenIteration(); lastSeen > 0 && lastSeen < iteration {
+				compareTo = lastSeen
+			}
+			if err := visits.RecordVisit(pr, latest); err != nil {
+				log.Error("Failed recording the visit of a pull request", "id", pr.ID, "err", err)
+			}
+		}
+
+		changes, err := client.FetchIterationChanges(ctx, pr, iteration, compareTo)
+		if err != nil {
+			return nil, fmt.Errorf("fetching the changes of PR #%d: %w", pr.ID, err)
+		}
+
+		return changesFetchedMsg{
+			PrId:       pr.ID,
+			Iterations: iterations,
+			Iteration:  iteration,
+			CompareTo:  compareTo,
+			Changes:    changes,
+		}, nil
+	})
+}
+
+func (m *Model) onChangesFetched(msg changesFetchedMsg) {
+	if !m.diff.isOpen || m.diff.pr.ID != msg.PrId {
+		return
+	}
+
+	for i := range m.Prs {
+		if m.Prs[i].ID == msg.PrId {
+			m.Prs[i].Iterations = msg.Iterations
+		}
+	}
+	// The preview marks the iterations that are new since the last visit.
+	m.previewRevision++
+
+	m.diff.isLoading = false
+	m.diff.iterations = msg.Iterations
+	m.diff.iteration = msg.Iteration
+	m.diff.compareTo = msg.CompareTo
+	m.diff.changes = msg.Changes
+	m.diff.fileCursor = 0
+	m.diff.files = map[string]diffview.File{}
+	m.diff.requested = map[string]bool{}
+	m.diff.renderedKey = diffKey{}
+}
+
+func (m *Model) onFileDiffFetched(msg fileDiffFetchedMsg) {
+	diff := &m.diff
+	if !diff.isOpen || diff.pr.ID != msg.PrId ||
+		diff.iteration != msg.Iteration || diff.compareTo != msg.CompareTo {
+		return
+	}
+	diff.files[msg.File.Change.Path] = msg.File
+}
+
+func (m *Model) getCurrChange() *data.FileChange {
+	cursor := m.diff.fileCursor
+	if cursor < 0 || cursor >= len(m.diff.changes) {
+		return nil
+	}
+	return &m.diff.changes[cursor]
+}
+
+// fetchFileDiff fetches the content of a changed file before and after the
+// change and diffs it.
+func (m *Model) fetchFileDiff(change data.FileChange) tea.Cmd {
+	client := m.Ctx.Client
+	pr := m.diff.pr
+	iteration := m.diff.iteration
+	compareTo := m.diff.compareTo
+	task := context.Task{
+		Id:           fmt.Sprintf("fetching_diff_%d_%s_%s", pr.ID, change.Path, time.Now().String()),
+		StartText:    fmt.Sprintf("Fetching the diff of %s", change.Path),
+		FinishedText: fmt.Sprintf("The diff of %s has been fetched", change.Path),
+	}
+
+	return m.RunTask(task, func() (tea.Msg, error) {
+		ctx := gocontext.Background()
+		oldContent, err := client.FetchBlob(ctx, pr, change.OriginalObjectID)
+		if err != nil {
+			return nil, fmt.Errorf("fetching %s before the change: %w", change.OriginalPath, err)
+		}
+		newContent, err := client.FetchBlob(ctx, pr, change.ObjectID)
+		if err != nil {
+			return nil, fmt.Errorf("fetching %s after the change: %w", change.Path, err)
+		}
+
+		return fileDiffFetchedMsg{
+			PrId:      pr.ID,
+			Iteration: iteration,
+			CompareTo: compareTo,
+			File:      diffview.NewFile(change, oldContent, newContent),
+		}, nil
+	})
+}
+
+func (m *Model) updateDiff(msg tea.KeyMsg) tea.Cmd {
+	diff := &m.diff
+
+	switch {
+	case key.Matches(msg, keys.DiffKeys.Close):
+		m.closeDiff()
+
+	case key.Matches(msg, keys.Keys.Up):
+		diff.viewport.LineUp(1)
+
+	case key.Matches(msg, keys.Keys.Down):
+		diff.viewport.LineDown(1)
+
+	case key.Matches(msg, keys.Keys.PageUp):
+		diff.viewport.HalfViewUp()
+
+	case key.Matches(msg, keys.Keys.PageDown):
+		diff.viewport.HalfViewDown()
+
+	case key.Matches(msg, keys.Keys.FirstLine):
+		diff.viewport.GotoTop()
+
+	case key.Matches(msg, keys.Keys.LastLine):
+		diff.viewport.GotoBottom()
+
+	case key.Matches(msg, keys.DiffKeys.NextFile):
+		diff.fileCursor = min(diff.fileCursor+1, max(0, len(diff.changes)-1))
+
+	case key.Matches(msg, keys.DiffKeys.PrevFile):
+		diff.fileCursor = max(0, diff.fileCursor-1)
+
+	case key.Matches(msg, keys.DiffKeys.NextHunk):
+		for _, offset := range diff.hunkOffsets {
+			if offset > diff.viewport.YOffset {
+				diff.viewport.SetYOffset(offset)
+				break
+			}
+		}
+
+	case key.Matches(msg, keys.DiffKeys.Iterations):
+		m.promptCompareIterations()
+
+	case key.Matches(msg, keys.DiffKeys.PrevHunk):
+		for i := len(diff.hunkOffsets) - 1; i >= 0; i-- {
+			if diff.hunkOffsets[i] < diff.viewport.YOffset {
+				diff.viewport.SetYOffset(diff.hunkOffsets[i])
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+func (m *Model) promptCompareIterations() {
+	diff := &m.diff
+	if diff.isLoading || len(diff.iterations) == 0 {
+		return
+	}
+
+	since := []string{targetBranchChoice}
+	upTo := make([]string, 0, len(diff.iterations))
+	sinceSelected, upToSelected := 0, 0
+	for i, iteration := range diff.iterations {
+		choice := strconv.Itoa(iteration.ID)
+		since = append(since, choice)
+		upTo = append(upTo, choice)
+		if iteration.ID == diff.compareTo {
+			sinceSelected = i + 1
+		}
+		if iteration.ID == diff.iteration {
+			upToSelected = i
+		}
+	}
+
+	m.ShowPrompt(
+		compareIterationsAction,
+		"Compare iterations?",
+		section.PromptOption{Key: "f", Label: sinceIterationOption, Choices: since, Selected: sinceSelected},
+		section.PromptOption{Key: "t", Label: upToIterationOption, Choices: upTo, Selected: upToSelected},
+	)
+}
+
+// compareIterations shows the changes between the iterations selected in the
+// prompt. Iterations selected the wrong way round are swapped.
+func (m *Model) compareIterations() tea.Cmd {
+	diff := &m.diff
+	sinceSelected := m.GetPromptSelection(sinceIterationOption)
+	upToSelected := m.GetPromptSelection(upToIterationOption)
+	if sinceSelected < 0 || upToSelected < 0 || upToSelected >= len(diff.iterations) {
+		return nil
+	}
+
+	compareTo := 0
+	if sinceSelected > 0 {
+		compareTo = diff.iterations[sinceSelected-1].ID
+	}
+	iteration := diff.iterations[upToSelected].ID
+	if compareTo > iteration {
+		compareTo, iteration = iteration, compareTo
+	}
+
+	diff.isLoading = true
+	return m.fetchChanges(diff.pr, iteration, compareTo)
+}
+
+// diffListWidth returns the outer width of the list of changed files.
+func (m *Model) diffListWidth() int {
+	return min(maxFileListWidth, m.Ctx.ScreenWidth/3)
+}
+
+// syncDiff fits the diff into the screen, renders the selected file and
+// fetches its diff the first time it is selected.
+func (m *Model) syncDiff() tea.Cmd {
+	diff := &m.diff
+	if !diff.isOpen {
+		return nil
+	}
+
+	// The header, the path of the file and the help line.
+	height := m.Ctx.MainContentHeight - 3
+	if m.IsPromptConfirmationShown {
+		height -= section.PromptHeight
+	}
+	diff.viewport.Width = max(1, m.Ctx.ScreenWidth-m.diffListWidth())
+	diff.viewport.Height = max(1, height)
+
+	change := m.getCurrChange()
+	if change == nil {
+		diff.viewport.SetContent("")
+		diff.renderedKey = diffKey{}
+		return nil
+	}
+
+	file, isLoaded := diff.files[change.Path]
+	key := diffKey{path: change.Path, width: diff.viewport.Width, isLoaded: isLoaded}
+	if key != diff.renderedKey {
+		content := emptyStyle.Render("Loading the diff...")
+		diff.hunkOffsets = nil
+		if isLoaded {
+			content, diff.hunkOffsets = file.View(diff.viewport.Width)
+		}
+		diff.viewport.SetContent(content)
+		if key.path != diff.renderedKey.path {
+			diff.viewport.GotoTop()
+		}
+		diff.renderedKey = key
+	}
+
+	if isLoaded || diff.requested[change.Path] {
+		return nil
+	}
+	diff.requested[change.Path] = true
+	return m.fetchFileDiff(*change)
+}
+
+func (m Model) viewDiff() string {
+	diff := m.diff
+	pr := diff.pr
+
+	header := fmt.Sprintf("Changes of PR #%d %s", pr.ID, pr.Title)
+	if !diff.isLoading {
+		since := "the target branch"
+		if diff.compareTo != 0 {
+			since = fmt.Sprintf("iteration %d", diff.compareTo)
+		}
+		header = fmt.Sprintf(
+			"%s · iteration %d of %d since %s · %d files",
+			header, diff.iteration, len(diff.iterations), since, len(diff.changes),
+		)
+	}
+	parts := []string{
+		lipgloss.NewStyle().MaxWidth(m.Ctx.ScreenWidth).Render(diffHeaderStyle.Render(header)),
+	}
+
+	height := diff.viewport.Height + 1
+	switch {
+	case diff.isLoading:
+		parts = append(parts, lipgloss.NewStyle().Height(height).Render(emptyStyle.Render("Loading changes...")))
+	case len(diff.changes) == 0:
+		parts = append(parts, lipgloss.NewStyle().Height(height).Render(emptyStyle.Render("No changes")))
+	default:
+		parts = append(parts, lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			m.viewFileList(height),
+			lipgloss.JoinVertical(
+				lipgloss.Left,
+				m.viewDiffPath(),
+				lipgloss.NewStyle().Height(diff.viewport.Height).Render(diff.viewport.View()),
+			),
+		))
+	}
+
+	if m.IsPromptConfirmationShown {
+		parts = append(parts, m.ViewPrompt())
+	}
+	parts = append(parts, threadsHelp.View(keys.DiffKeys))
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
+
+// viewFileList renders the changed files, scrolled so the selected one is
+// visible.
+func (m Model) viewFileList(height int) string {
+	width := m.diffListWidth() - fileListStyle.GetHorizontalFrameSize()
+	lines := strings.Split(strings.TrimSuffix(
+		diffview.FileList(m.diff.changes, m.diff.fileCursor, width), "\n"), "\n")
+
+	start := max(0, m.diff.fileCursor-height+1)
+	end := min(len(lines), start+height)
+	return fileListStyle.
+		Width(width).
+		Height(height).
+		Render(strings.Join(lines[start:end], "\n"))
+}
+
+func (m Model) viewDiffPath() string {
+	change := m.getCurrChange()
+	if change == nil {
+		return ""
+	}
+
+	path := diffPathStyle.Render(change.Path)
+	if change.OriginalPath != change.Path {
+		path += emptyStyle.Render(fmt.Sprintf("renamed from %s", change.OriginalPath))
+	}
+	return lipgloss.NewStyle().MaxWidth(m.diff.viewport.Width).PaddingLeft(1).Render(path)
+}
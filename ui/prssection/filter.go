@@ -0,0 +1,164 @@
+package prssection
+
+import (
+	"azdo-dash/data"
+	"azdo-dash/ui/section"
+	"cmp"
+	"fmt"
+	"github.com/charmbracelet/x/ansi"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const sortAction = "sort"
+
+const (
+	sortColumnOption = "column"
+	sortOrderOption  = "order"
+)
+
+const (
+	sortAscending  = "ascending"
+	sortDescending = "descending"
+)
+
+// noSortColumn keeps the rows in the order they were fetched in.
+const noSortColumn = -1
+
+func (col column) textOf(pr data.PullRequestData) string {
+	if col.text != nil {
+		return col.text(pr)
+	}
+	return ansi.Strip(col.value(pr))
+}
+
+func (col column) compare(a data.PullRequestData, b data.PullRequestData) int {
+	if col.number != nil {
+		return cmp.Compare(col.number(a), col.number(b))
+	}
+	return strings.Compare(strings.ToLower(col.textOf(a)), strings.ToLower(col.textOf(b)))
+}
+
+// syncRows filters and sorts the pull requests into the rows of the table.
+func (m *Model) syncRows() {
+	rows := make([]int, 0, len(m.Prs))
+	for i, pr := range m.Prs {
+		if matchesFilter(pr, m.SearchValue) {
+			rows = append(rows, i)
+		}
+	}
+
+	if m.sortColumn != noSortColumn {
+		col := columns[m.sortColumn]
+		slices.SortStableFunc(rows, func(a int, b int) int {
+			order := col.compare(m.Prs[a], m.Prs[b])
+			if m.sortDescending {
+				return -order
+			}
+			return order
+		})
+	}
+
+	m.rows = rows
+}
+
+// matchesFilter reports whether pr matches all terms of filter. A term of the
+// form column:value only matches the column with a title starting with
+// column, other terms match any column. Numeric columns also match the
+// comparisons >n, <n and =n.
+func matchesFilter(pr data.PullRequestData, filter string) bool {
+	for _, term := range strings.Fields(strings.ToLower(filter)) {
+		if !matchesTerm(pr, term) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesTerm(pr data.PullRequestData, term string) bool {
+	if name, value, ok := strings.Cut(term, ":"); ok {
+		for _, col := range columns {
+			if strings.HasPrefix(strings.ToLower(col.title), name) {
+				return col.matches(pr, value)
+			}
+		}
+	}
+
+	for _, col := range columns {
+		if strings.Contains(strings.ToLower(col.textOf(pr)), term) {
+			return true
+		}
+	}
+	return false
+}
+
+func (col column) matches(pr data.PullRequestData, value string) bool {
+	if col.number != nil && len(value) > 1 {
+		if n, err := strconv.Atoi(value[1:]); err == nil {
+			switch value[0] {
+			case '>':
+				return col.number(pr) > n
+			case '<':
+				return col.number(pr) < n
+			case '=':
+				return col.number(pr) == n
+			}
+		}
+	}
+	return strings.Contains(strings.ToLower(col.textOf(pr)), value)
+}
+
+func (m *Model) promptSort() {
+	titles := make([]string, 0, len(columns)+1)
+	titles = append(titles, "none")
+	for _, col := range columns {
+		titles = append(titles, col.title)
+	}
+
+	order := 0
+	if m.sortDescending {
+		order = 1
+	}
+	m.ShowPrompt(
+		sortAction,
+		"Sort the pull requests?",
+		section.PromptOption{Key: "c", Label: sortColumnOption, Choices: titles, Selected: m.sortColumn + 1},
+		section.PromptOption{Key: "o", Label: sortOrderOption, Choices: []string{sortAscending, sortDescending}, Selected: order},
+	)
+}
+
+func (m *Model) sort() {
+	m.sortColumn = m.GetPromptSelection(sortColumnOption) - 1
+	m.sortDescending = m.GetPromptOption(sortOrderOption) == sortDescending
+}
+
+// viewFilterStatus describes the filter and sorting applied to the rows.
+func (m Model) viewFilterStatus() string {
+	if m.IsSearching {
+		return m.ViewSearch()
+	}
+
+	var parts []string
+	if m.SearchValue != "" {
+		parts = append(parts, fmt.Sprintf("Filter: %s", m.SearchValue))
+	}
+	if m.sortColumn != noSortColumn {
+		order := sortAscending
+		if m.sortDescending {
+			order = sortDescending
+		}
+		parts = append(parts, fmt.Sprintf("Sorted by %s (%s)", columns[m.sortColumn].title, order))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	parts = append(parts, fmt.Sprintf("%d of %d pull requests", len(m.rows), len(m.Prs)))
+
+	return emptyStyle.Render(strings.Join(parts, " · "))
+}
+
+// hasFilterStatus reports whether viewFilterStatus takes up a line.
+func (m Model) hasFilterStatus() bool {
+	return m.IsSearching || m.SearchValue != "" || m.sortColumn != noSortColumn
+}
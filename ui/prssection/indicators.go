@@ -0,0 +1,84 @@
+package prssection
+
+import (
+	"azdo-dash/constants"
+	"azdo-dash/data"
+	gocontext "context"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type indicatorsFetchedMsg struct {
+	PrId         int
+	RepositoryId string
+	Indicators   data.PullRequestIndicators
+}
+
+// fetchIndicators fetches the indicators of listed pull requests with at most
+// the configured concurrency of pull requests in flight. Every pull request is
+// updated on its own, so the rows show before all indicators are known.
+func (m *Model) fetchIndicators(prs []data.PullRequestData) []tea.Cmd {
+	if m.indicatorsCtx == nil {
+		m.indicatorsCtx, m.cancelIndicators = gocontext.WithCancel(gocontext.Background())
+	}
+
+	ctx := m.indicatorsCtx
+	client := m.Ctx.Client
+	concurrency := m.Ctx.Config.Defaults.Concurrency
+	if concurrency <= 0 {
+		concurrency = data.DefaultConcurrency
+	}
+	slots := make(chan struct{}, concurrency)
+
+	id := m.Id
+	cmds := make([]tea.Cmd, 0, len(prs))
+	for _, pr := range prs {
+		cmds = append(cmds, func() tea.Msg {
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				return nil
+			}
+
+			indicators := client.FetchPullRequestIndicators(ctx, pr)
+			if ctx.Err() != nil {
+				return nil
+			}
+			return constants.SectionMsg{
+				SectionId:   id,
+				SectionType: SectionType,
+				Msg: indicatorsFetchedMsg{
+					PrId:         pr.ID,
+					RepositoryId: pr.RepositoryID,
+					Indicators:   indicators,
+				},
+			}
+		})
+	}
+
+	return cmds
+}
+
+func (m *Model) onIndicatorsFetched(msg indicatorsFetchedMsg) {
+	for i := range m.Prs {
+		if m.Prs[i].ID == msg.PrId && m.Prs[i].RepositoryID == msg.RepositoryId {
+			m.Prs[i].SetIndicators(msg.Indicators)
+			m.previewRevision++
+		}
+	}
+}
+
+// cancelIndicatorsFetch stops fetching the indicators of the rows, e.g.
+// because they are fetched again.
+func (m *Model) cancelIndicatorsFetch() {
+	if m.cancelIndicators != nil {
+		m.cancelIndicators()
+	}
+	m.indicatorsCtx = nil
+	m.cancelIndicators = nil
+}
+
+func (m *Model) CancelFetch() {
+	m.Model.CancelFetch()
+	m.cancelIndicatorsFetch()
+}
@@ -0,0 +1,126 @@
+package prssection
+
+import (
+	"azdo-dash/constants"
+	"azdo-dash/context"
+	"azdo-dash/data"
+	"azdo-dash/ui/prview"
+	gocontext "context"
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"time"
+)
+
+type pullRequestFetchedMsg struct {
+	Pr data.PullRequestData
+}
+
+// previewFetchDelay is how long a pull request stays selected before the
+// preview fetches it, scrolling through the rows fetches none of them.
+const previewFetchDelay = 300 * time.Millisecond
+
+type previewFetchTickMsg struct {
+	PrId int
+}
+
+// previewKey identifies what the preview currently shows, the preview is
+// rendered again whenever it changes.
+type previewKey struct {
+	prId     int
+	width    int
+	revision int
+}
+
+// syncPreview renders the selected pull request into the preview. The first
+// time a pull request is previewed for previewFetchDelay it is fetched again,
+// as only the single pull request carries the full description.
+func (m *Model) syncPreview() tea.Cmd {
+	if !m.Ctx.PreviewOpen {
+		return nil
+	}
+
+	m.sidebar.SetSize(m.Ctx.PreviewWidth, m.Ctx.MainContentHeight)
+
+	pr := m.GetCurrPr()
+	if pr == nil {
+		m.sidebar.SetContent("")
+		m.previewKey = previewKey{}
+		return nil
+	}
+
+	width := m.sidebar.GetContentWidth()
+	key := previewKey{prId: pr.ID, width: width, revision: m.previewRevision}
+	if key != m.previewKey {
+		lastSeen := m.Ctx.Visits.Get(*pr).LastSeenIteration()
+		m.sidebar.SetContent(prview.View(*pr, lastSeen, width))
+		if key.prId != m.previewKey.prId {
+			m.sidebar.GotoTop()
+		}
+		m.previewKey = key
+	}
+
+	if m.fetchedPrs[pr.ID] || m.pendingPreviewPr == pr.ID {
+		return nil
+	}
+	m.pendingPreviewPr = pr.ID
+	id := m.Id
+	prId := pr.ID
+	return tea.Tick(previewFetchDelay, func(time.Time) tea.Msg {
+		return constants.SectionMsg{
+			SectionId:   id,
+			SectionType: SectionType,
+			Msg:         previewFetchTickMsg{PrId: prId},
+		}
+	})
+}
+
+// onPreviewFetchTick fetches the pull request if it is still selected, and
+// no other pull request was selected in the meantime.
+func (m *Model) onPreviewFetchTick(msg previewFetchTickMsg) tea.Cmd {
+	if m.pendingPreviewPr != msg.PrId {
+		return nil
+	}
+	m.pendingPreviewPr = 0
+
+	pr := m.GetCurrPr()
+	if !m.Ctx.PreviewOpen || pr == nil || pr.ID != msg.PrId || m.fetchedPrs[pr.ID] {
+		return nil
+	}
+	m.fetchedPrs[pr.ID] = true
+	return m.fetchPr(*pr)
+}
+
+func (m *Model) fetchPr(pr data.PullRequestData) tea.Cmd {
+	client := m.Ctx.Client
+	user := *m.Ctx.User
+	task := context.Task{
+		Id:           fmt.Sprintf("fetching_pr_%d_%s", pr.ID, time.Now().String()),
+		StartText:    fmt.Sprintf("Fetching PR #%d", pr.ID),
+		FinishedText: fmt.Sprintf("PR #%d has been fetched", pr.ID),
+	}
+
+	return m.RunTask(task, func() (tea.Msg, error) {
+		updatedPr, err := client.FetchPullRequest(
+			gocontext.Background(),
+			pr.ProjectID,
+			pr.RepositoryID,
+			pr.ID,
+			user,
+		)
+		if err != nil {
+			return nil, err
+		}
+		return pullRequestFetchedMsg{Pr: updatedPr}, nil
+	})
+}
+
+// updatePr replaces the row of the pull request in place.
+func (m *Model) updatePr(pr data.PullRequestData) {
+	for i := range m.Prs {
+		if m.Prs[i].ID == pr.ID {
+			m.Prs[i] = pr
+			m.previewRevision++
+			return
+		}
+	}
+}
@@ -1,11 +1,19 @@
 package prssection
 
 import (
+	"azdo-dash/config"
 	"azdo-dash/constants"
 	"azdo-dash/context"
 	"azdo-dash/data"
+	"azdo-dash/ui/identitypicker"
+	"azdo-dash/ui/keys"
 	"azdo-dash/ui/section"
+	"azdo-dash/ui/sidebar"
+	gocontext "context"
+	"errors"
 	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"strings"
@@ -14,175 +22,381 @@ import (
 
 const SectionType = "pr"
 
+// nextPageThreshold is how close the cursor has to get to the last row
+// before the next page is loaded.
+const nextPageThreshold = 5
+
 type SectionPullRequestsFetchedMsg struct {
-	Prs        []data.PullRequestData
-	TotalCount int
-	TaskId     string
+	Prs              []data.PullRequestData
+	TotalCount       int
+	Errors           []data.RepositoryError
+	NextPageRequests []data.FetchPRRequest
+	TaskId           string
 }
 
 type Model struct {
 	section.Model
-	Prs        []data.PullRequestData
-	TotalCount int
+	Prs              []data.PullRequestData
+	rows             []int
+	sortColumn       int
+	sortDescending   bool
+	FetchErrors      []data.RepositoryError
+	NextPageRequests []data.FetchPRRequest
+	sidebar          sidebar.Model
+	previewKey       previewKey
+	previewRevision  int
+	fetchedPrs       map[int]bool
+	pendingPreviewPr int
+	reviewerPicker   reviewerPicker
+	threads          threadsView
+	diff             diffView
+	// promptPr is the pull request the shown prompt acts on.
+	promptPr         data.PullRequestData
+	indicatorsCtx    gocontext.Context
+	cancelIndicators gocontext.CancelFunc
 }
 
 func NewModel(
 	id int,
 	ctx *context.ProgramContext,
+	cfg config.SectionConfig,
 	lastUpdated time.Time,
 ) Model {
 	m := Model{}
 	m.Model = section.NewModel(
 		id,
 		ctx,
+		cfg,
 		SectionType,
 		lastUpdated,
 	)
-	m.Prs = []data.PullRequestData{}
+	m.Table = table.New(
+		table.WithFocused(true),
+		table.WithStyles(section.TableStyles),
+		table.WithKeyMap(keys.TableKeyMap()),
+	)
+	m.sidebar = sidebar.NewModel()
+	m.sortColumn = noSortColumn
+	m.ResetRows()
+	m.syncTable()
 
 	return m
 }
 
 func (m Model) Update(msg tea.Msg) (section.Section, tea.Cmd) {
-	var cmd tea.Cmd
+	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
 
 	case SectionPullRequestsFetchedMsg:
 		if m.LastFetchTaskId == msg.TaskId {
-			m.Prs = msg.Prs
+			m.Prs = append(m.Prs, msg.Prs...)
+
+			m.TotalCount = len(m.Prs)
+			m.FetchErrors = append(m.FetchErrors, msg.Errors...)
+			m.NextPageRequests = msg.NextPageRequests
+			m.IsLoading = false
+			cmds = append(cmds, m.fetchIndicators(msg.Prs)...)
+		}
+
+	case indicatorsFetchedMsg:
+		m.onIndicatorsFetched(msg)
 
-			m.TotalCount = msg.TotalCount
+	case pullRequestFetchedMsg:
+		m.updatePr(msg.Pr)
+
+	case previewFetchTickMsg:
+		cmds = append(cmds, m.onPreviewFetchTick(msg))
+
+	case identitypicker.IdentitiesFoundMsg:
+		m.reviewerPicker.SetIdentities(msg)
+
+	case threadsFetchedMsg:
+		m.onThreadsFetched(msg)
+
+	case changesFetchedMsg:
+		m.onChangesFetched(msg)
+
+	case fileDiffFetchedMsg:
+		m.onFileDiffFetched(msg)
+
+	case tea.KeyMsg:
+		if m.IsPromptConfirmationShown {
+			if m.UpdatePrompt(msg) == section.PromptConfirmed {
+				cmds = append(cmds, m.onPromptConfirmed())
+			}
+			break
+		}
+		if m.IsSearching {
+			cmds = append(cmds, m.UpdateSearch(msg))
+			break
+		}
+		if m.reviewerPicker.IsOpen {
+			cmds = append(cmds, m.updateReviewerPicker(msg))
+			break
+		}
+		if m.threads.isOpen {
+			cmds = append(cmds, m.updateThreads(msg))
+			break
+		}
+		if m.diff.isOpen {
+			cmds = append(cmds, m.updateDiff(msg))
+			break
+		}
+
+		switch {
+		case key.Matches(msg, keys.PrKeys.OpenInBrowser):
+			cmds = append(cmds, m.openInBrowser())
+
+		case key.Matches(msg, keys.PrKeys.Approve):
+			cmds = append(cmds, m.vote(data.VoteApproved))
+
+		case key.Matches(msg, keys.PrKeys.ApproveWithSuggestions):
+			cmds = append(cmds, m.vote(data.VoteApprovedWithSuggestions))
+
+		case key.Matches(msg, keys.PrKeys.WaitForAuthor):
+			cmds = append(cmds, m.vote(data.VoteWaitingForAuthor))
+
+		case key.Matches(msg, keys.PrKeys.Reject):
+			cmds = append(cmds, m.vote(data.VoteRejected))
+
+		case key.Matches(msg, keys.PrKeys.ResetVote):
+			cmds = append(cmds, m.vote(data.VoteNone))
+
+		case key.Matches(msg, keys.PrKeys.Complete):
+			m.promptComplete()
+
+		case key.Matches(msg, keys.PrKeys.AutoComplete):
+			m.promptAutoComplete()
+
+		case key.Matches(msg, keys.Keys.Filter):
+			m.StartSearch()
+
+		case key.Matches(msg, keys.Keys.Sort):
+			m.promptSort()
+
+		case key.Matches(msg, keys.PrKeys.Threads):
+			cmds = append(cmds, m.openThreads())
+
+		case key.Matches(msg, keys.PrKeys.Diff):
+			cmds = append(cmds, m.openDiff())
+
+		case key.Matches(msg, keys.PrKeys.Checkout):
+			m.promptCheckout()
+
+		case key.Matches(msg, keys.PrKeys.OpenWorkItem):
+			cmds = append(cmds, m.openWorkItem())
+
+		case key.Matches(msg, keys.PrKeys.AddReviewer):
+			m.openReviewerPicker()
+
+		case key.Matches(msg, keys.PrKeys.RemoveReviewer):
+			m.promptRemoveReviewer()
+
+		case key.Matches(msg, keys.PrKeys.Abandon):
+			m.promptAbandon()
+
+		case key.Matches(msg, keys.PrKeys.ToggleDraft):
+			m.promptToggleDraft()
+
+		case key.Matches(msg, keys.Keys.PreviewUp, keys.Keys.PreviewDown):
+			var cmd tea.Cmd
+			m.sidebar, cmd = m.sidebar.Update(msg)
+			cmds = append(cmds, cmd)
+
+		default:
+			var cmd tea.Cmd
+			m.Table, cmd = m.Table.Update(msg)
+			cmds = append(cmds, cmd)
 		}
 	}
 
-	return &m, tea.Batch(cmd)
+	m.syncTable()
+	m.syncThreads()
+	cmds = append(cmds, m.syncDiff())
+	cmds = append(cmds, m.syncPreview())
+
+	if m.isNearLastRow() {
+		cmds = append(cmds, m.FetchNextPageSectionRows()...)
+	}
+
+	return &m, tea.Batch(cmds...)
 }
 
-var (
-	headerStyle             = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
-	rowStyle                = lipgloss.NewStyle()
-	columnStyle             = lipgloss.NewStyle().Width(22).PaddingRight(2)
-	smallColumnStyle        = lipgloss.NewStyle().Width(10).PaddingRight(2).MaxWidth(10).AlignHorizontal(lipgloss.Center)
-	statusActive            = lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Render("●")
-	statusCompleted         = lipgloss.NewStyle().Foreground(lipgloss.Color("6")).Render("●")
-	statusDraft             = lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render("●")
-	checkMark               = lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Render("✓")
-	crossMark               = lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render("✗")
-	noVote                  = lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render("")
-	approved                = lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Render("✓")
-	approvedWithSuggestions = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("✓✍")
-	waitForAuthor           = lipgloss.NewStyle().Foreground(lipgloss.Color("4")).Render("⌛")
-	rejected                = lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render("✗")
-)
+func (m *Model) IsCapturingKeys() bool {
+	return m.Model.IsCapturingKeys() || m.reviewerPicker.IsOpen || m.threads.isOpen || m.diff.isOpen
+}
 
-func truncateString(str string, maxWidth int) string {
-	if len(str) > maxWidth {
-		if maxWidth > 3 {
-			return str[:maxWidth-3] + "..."
-		}
-		return str[:maxWidth]
+// GetCurrPr returns the selected pull request, or nil if there is none.
+func (m *Model) GetCurrPr() *data.PullRequestData {
+	cursor := m.Table.Cursor()
+	if cursor < 0 || cursor >= len(m.rows) {
+		return nil
 	}
-	return str
+	return &m.Prs[m.rows[cursor]]
 }
 
-func formatStatus(status string) string {
-	switch status {
-	case "active":
-		return statusActive
-	case "completed":
-		return statusCompleted
-	case "draft":
-		return statusDraft
-	default:
-		return status
+// syncTable fits the table into the main content area and renders the rows
+// for the current cursor position.
+func (m *Model) syncTable() {
+	m.syncRows()
+
+	height := m.Ctx.MainContentHeight - section.TableHeaderHeight
+	if len(m.rows) == 0 {
+		height--
+	}
+	if m.hasFilterStatus() {
+		height--
+	}
+	if len(m.FetchErrors) > 0 {
+		// A blank line and a header precede the failed repositories.
+		height -= len(m.FetchErrors) + 2
+	}
+	if m.IsPromptConfirmationShown {
+		height -= section.PromptHeight
+	}
+	if m.reviewerPicker.IsOpen {
+		height -= identitypicker.Height
 	}
+	m.Table.SetHeight(max(1, height))
+	m.Table.SetWidth(m.Ctx.MainContentWidth)
+	// The table renders its rows with the new columns right away, so rows
+	// of the columns shown before must not outlive them.
+	tableColumns, shown := tableColumns(m.Ctx.MainContentWidth)
+	m.Table.SetRows(nil)
+	m.Table.SetColumns(tableColumns)
+	// The table moves its cursor to -1 while it has no rows.
+	cursor := max(0, min(m.Table.Cursor(), len(m.rows)-1))
+	prs := make([]data.PullRequestData, 0, len(m.rows))
+	for _, i := range m.rows {
+		prs = append(prs, m.Prs[i])
+	}
+	m.Table.SetRows(tableRows(prs, shown, cursor))
+	m.Table.SetCursor(cursor)
 }
 
-func formatVote(vote int) string {
-	switch vote {
-	case 0:
-		return noVote
-	case 10:
-		return approved
-	case 5:
-		return approvedWithSuggestions
-	case -5:
-		return waitForAuthor
-	case -10:
-		return rejected
-	default:
-		return noVote
+func (m *Model) isNearLastRow() bool {
+	if m.IsLoading || len(m.NextPageRequests) == 0 {
+		return false
 	}
+	return len(m.rows)-m.Table.Cursor() <= nextPageThreshold
+}
+
+func (m *Model) ResetRows() {
+	m.cancelIndicatorsFetch()
+	m.Table.SetCursor(0)
+	m.Prs = []data.PullRequestData{}
+	m.fetchedPrs = map[int]bool{}
+	m.TotalCount = 0
+	m.FetchErrors = []data.RepositoryError{}
+	m.NextPageRequests = m.firstPageRequests()
+	m.IsLoading = false
 }
 
-func formatBool(value bool) string {
-	if value {
-		return checkMark
+func (m *Model) firstPageRequests() []data.FetchPRRequest {
+	var requests []data.FetchPRRequest
+	if m.Ctx == nil || m.Ctx.Config == nil {
+		return requests
+	}
+
+	projects := m.Config.Projects
+	if len(projects) == 0 {
+		projects = m.Ctx.Config.Projects
 	}
-	return crossMark
+
+	defaults := m.Ctx.Config.Defaults
+	searchCriteria := m.searchCriteria()
+	for _, project := range projects {
+		for _, repoId := range project.RepoIds {
+			requests = append(requests, data.FetchPRRequest{
+				ProjectID:      project.Id,
+				RepoID:         repoId,
+				SearchCriteria: searchCriteria,
+				PageSize:       defaults.PageSize,
+				Limit:          defaults.PrsLimit,
+			})
+		}
+	}
+
+	return requests
 }
 
-func removePrefix(refName string) string {
-	return strings.TrimPrefix(refName, "refs/heads/")
+func (m *Model) searchCriteria() data.SearchCriteria {
+	var user data.User
+	if m.Ctx.User != nil {
+		user = *m.Ctx.User
+	}
+	return data.NewSearchCriteria(m.Config.Filters, user, time.Now())
 }
 
+var (
+	errorHeaderStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("1"))
+	errorStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	emptyStyle       = lipgloss.NewStyle().Faint(true).Padding(0, 1)
+)
+
 func (m Model) View() string {
+	if m.threads.isOpen {
+		return m.viewThreads()
+	}
+	if m.diff.isOpen {
+		return m.viewDiff()
+	}
+
 	s := strings.Builder{}
 
-	headers := []string{"Repository", "Title", "CreatedBy", "Status", "Required", "Vote", "SourceBranch", "IsDraft"}
-	for _, header := range headers {
-		switch header {
-		case "Status", "IsDraft", "Vote", "Required":
-			s.WriteString(headerStyle.Render(smallColumnStyle.Render(header)))
+	s.WriteString(m.Table.View())
+	s.WriteString("\n")
+	if len(m.rows) == 0 {
+		switch {
+		case m.IsLoading:
+			s.WriteString(emptyStyle.Render("Loading pull requests..."))
+		case len(m.Prs) > 0:
+			s.WriteString(emptyStyle.Render("No pull requests match the filter"))
 		default:
-			s.WriteString(headerStyle.Render(columnStyle.Render(header)))
+			s.WriteString(emptyStyle.Render("No pull requests"))
 		}
+		s.WriteString("\n")
+	}
+	if m.hasFilterStatus() {
+		s.WriteString(m.viewFilterStatus())
+		s.WriteString("\n")
 	}
-	s.WriteString("\n")
 
-	for _, pr := range m.Prs {
-
-		row := []string{
-			pr.RepositoryName,
-			pr.Title,
-			pr.CreatedBy,
-			formatStatus(pr.Status),
-			formatBool(pr.IsRequiredReviewer),
-			formatVote(pr.Vote),
-			removePrefix(pr.SourceBranch),
-			formatBool(pr.IsDraft),
-		}
+	s.WriteString(m.viewFetchErrors())
+	s.WriteString(m.ViewPrompt())
+	s.WriteString(m.viewReviewerPicker())
 
-		for i, col := range row {
-			switch headers[i] {
-			case "Status", "Required", "Vote", "IsDraft":
-				s.WriteString(rowStyle.Render(smallColumnStyle.Render(col)))
-			default:
-				s.WriteString(rowStyle.Render(columnStyle.Render(truncateString(col, 20))))
-			}
-		}
-		s.WriteString("\n")
+	if !m.Ctx.PreviewOpen {
+		return s.String()
 	}
 
-	return s.String()
+	return lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		lipgloss.NewStyle().Width(m.Ctx.MainContentWidth).Render(s.String()),
+		m.sidebar.View(),
+	)
 }
 
-func FetchAllSections(
-	ctx context.ProgramContext,
-) (sections []section.Section, fetchAllCmd tea.Cmd) {
-	fetchPRsCmds := make([]tea.Cmd, 0)
-	sections = make([]section.Section, 0)
-	sectionModel := NewModel(
-		1,
-		&ctx,
-		time.Now(),
-	) // 0 is the search section
-	sections = append(sections, &sectionModel)
-	fetchPRsCmds = append(
-		fetchPRsCmds,
-		sectionModel.FetchNextPageSectionRows()...)
-	return sections, tea.Batch(fetchPRsCmds...)
+func (m Model) viewFetchErrors() string {
+	if len(m.FetchErrors) == 0 {
+		return ""
+	}
+
+	s := strings.Builder{}
+	s.WriteString("\n")
+	s.WriteString(errorHeaderStyle.Render(
+		fmt.Sprintf("Failed to load %d of the repositories:", len(m.FetchErrors)),
+	))
+	s.WriteString("\n")
+	for _, repoErr := range m.FetchErrors {
+		s.WriteString(errorStyle.Render(
+			fmt.Sprintf("  %s/%s: %s", repoErr.ProjectID, repoErr.RepoID, repoErr.Reason()),
+		))
+		s.WriteString("\n")
+	}
+
+	return s.String()
 }
 
 func (m *Model) FetchNextPageSectionRows() []tea.Cmd {
@@ -192,28 +406,28 @@ func (m *Model) FetchNextPageSectionRows() []tea.Cmd {
 
 	var cmds []tea.Cmd
 
-	var requests []data.FetchPRRequest
-	for _, project := range m.Ctx.Config.Projects {
-		for _, repoId := range project.RepoIds {
-			requests = append(requests, data.FetchPRRequest{
-				OrgName:             m.Ctx.Config.OrgName,
-				ProjectID:           project.Id,
-				RepoID:              repoId,
-				PersonalAccessToken: m.Ctx.Config.PersonalAccessToken,
-			})
-		}
+	requests := m.NextPageRequests
+	if len(requests) == 0 {
+		return nil
 	}
+	m.IsLoading = true
+
+	client := m.Ctx.Client
+	user := *m.Ctx.User
+	concurrency := m.Ctx.Config.Defaults.Concurrency
+	fetchCtx := m.NewFetchContext()
 
 	startCursor := time.Now().String()
-	id := 1
+	id := m.Id
+	title := m.Config.Title
 	taskId := fmt.Sprintf("fetching_prs_%d_%s", id, startCursor)
 	m.LastFetchTaskId = taskId
 	task := context.Task{
 		Id:        taskId,
-		StartText: fmt.Sprintf(`Fetching PRs for "%s"`, m.Ctx.Config.OrgName),
+		StartText: fmt.Sprintf(`Fetching PRs for "%s"`, m.Config.Title),
 		FinishedText: fmt.Sprintf(
 			`PRs for "%s" have been fetched`,
-			m.Ctx.Config.OrgName,
+			m.Config.Title,
 		),
 		State: context.TaskStart,
 		Error: nil,
@@ -222,7 +436,17 @@ func (m *Model) FetchNextPageSectionRows() []tea.Cmd {
 	cmds = append(cmds, startCmd)
 
 	fetchCmd := func() tea.Msg {
-		res, err := data.FetchPullRequests(requests)
+		res, err := client.FetchPullRequests(fetchCtx, requests, user, concurrency)
+		// Refreshing cancels the fetch on purpose, the section waits for the
+		// fetch that replaced it.
+		if errors.Is(err, gocontext.Canceled) && fetchCtx.Err() != nil {
+			return constants.TaskFinishedMsg{
+				SectionId:    id,
+				SectionType:  SectionType,
+				TaskId:       taskId,
+				FinishedText: fmt.Sprintf(`Fetching PRs for "%s" was cancelled`, title),
+			}
+		}
 		if err != nil {
 			return constants.TaskFinishedMsg{
 				SectionId:   id,
@@ -232,14 +456,23 @@ func (m *Model) FetchNextPageSectionRows() []tea.Cmd {
 			}
 		}
 
+		// Only fail the task if nothing could be loaded, otherwise the
+		// failing repositories are listed below the table.
+		if len(requests) > 0 && len(res.Errors) == len(requests) {
+			err = fmt.Errorf("fetching pull requests of all repositories failed: %w", res.Errors[0])
+		}
+
 		return constants.TaskFinishedMsg{
 			SectionId:   id,
 			SectionType: SectionType,
 			TaskId:      taskId,
+			Err:         err,
 			Msg: SectionPullRequestsFetchedMsg{
-				Prs:        res.Prs,
-				TotalCount: res.TotalCount,
-				TaskId:     taskId,
+				Prs:              res.Prs,
+				TotalCount:       res.TotalCount,
+				Errors:           res.Errors,
+				NextPageRequests: res.NextPageRequests,
+				TaskId:           taskId,
 			},
 		}
 	}
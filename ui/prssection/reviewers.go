@@ -0,0 +1,125 @@
+package prssection
+
+import (
+	"azdo-dash/context"
+	"azdo-dash/data"
+	"azdo-dash/ui/identitypicker"
+	"azdo-dash/ui/keys"
+	"azdo-dash/ui/section"
+	gocontext "context"
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"time"
+)
+
+const removeReviewerAction = "remove_reviewer"
+
+const reviewerOption = "reviewer"
+
+// reviewerPicker picks an identity to add as a reviewer of pr, as a required
+// or an optional one.
+type reviewerPicker struct {
+	identitypicker.Model
+	pr         data.PullRequestData
+	isRequired bool
+}
+
+func (m *Model) openReviewerPicker() {
+	pr := m.GetCurrPr()
+	if pr == nil {
+		return
+	}
+
+	m.reviewerPicker = reviewerPicker{
+		Model: identitypicker.New("Add reviewer: "),
+		pr:    *pr,
+	}
+}
+
+func (m *Model) updateReviewerPicker(msg tea.KeyMsg) tea.Cmd {
+	picker := &m.reviewerPicker
+	if key.Matches(msg, keys.InputKeys.ToggleRequired) {
+		picker.isRequired = !picker.isRequired
+		return nil
+	}
+
+	action, cmd := picker.Update(msg)
+	switch action {
+	case identitypicker.Search:
+		return m.SearchIdentities(picker.Query())
+	case identitypicker.Pick:
+		identity, _ := picker.Selected()
+		return m.addReviewer(picker.pr, identity, picker.isRequired)
+	}
+	return cmd
+}
+
+func (m *Model) addReviewer(pr data.PullRequestData, identity data.User, isRequired bool) tea.Cmd {
+	client := m.Ctx.Client
+	kind := "an optional"
+	if isRequired {
+		kind = "a required"
+	}
+	task := context.Task{
+		Id:           fmt.Sprintf("add_reviewer_pr_%d_%s", pr.ID, time.Now().String()),
+		StartText:    fmt.Sprintf("Adding %s to PR #%d", identity.DisplayName, pr.ID),
+		FinishedText: fmt.Sprintf("%s is now %s reviewer of PR #%d", identity.DisplayName, kind, pr.ID),
+	}
+
+	return m.runPrTask(pr, task, func(ctx gocontext.Context) error {
+		return client.AddReviewer(ctx, pr, identity.ID, isRequired)
+	})
+}
+
+func (m *Model) promptRemoveReviewer() {
+	pr := m.GetCurrPr()
+	if pr == nil || len(pr.Reviewers) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(pr.Reviewers))
+	for _, reviewer := range pr.Reviewers {
+		names = append(names, reviewer.DisplayName)
+	}
+	m.showPrPrompt(
+		*pr,
+		removeReviewerAction,
+		fmt.Sprintf("Remove a reviewer from PR #%d?", pr.ID),
+		section.PromptOption{Key: "r", Label: reviewerOption, Choices: names},
+	)
+}
+
+// removeReviewer removes the reviewer picked in the prompt. The choices of
+// the prompt are the reviewers of promptPr, which keeps them as they were
+// when the prompt opened.
+func (m *Model) removeReviewer() tea.Cmd {
+	pr := m.promptPr
+	index := m.GetPromptSelection(reviewerOption)
+	if index < 0 || index >= len(pr.Reviewers) {
+		return nil
+	}
+
+	client := m.Ctx.Client
+	reviewer := pr.Reviewers[index]
+	task := context.Task{
+		Id:           fmt.Sprintf("remove_reviewer_pr_%d_%s", pr.ID, time.Now().String()),
+		StartText:    fmt.Sprintf("Removing %s from PR #%d", reviewer.DisplayName, pr.ID),
+		FinishedText: fmt.Sprintf("%s no longer reviews PR #%d", reviewer.DisplayName, pr.ID),
+	}
+
+	return m.runPrTask(pr, task, func(ctx gocontext.Context) error {
+		return client.RemoveReviewer(ctx, pr, reviewer.ID)
+	})
+}
+
+func (m Model) viewReviewerPicker() string {
+	kind := "optional"
+	if m.reviewerPicker.isRequired {
+		kind = "required"
+	}
+	return m.reviewerPicker.View(m.Ctx.MainContentWidth, fmt.Sprintf(
+		"adding as %s • enter search/add • ↑/↓ select • ctrl+r required/optional • esc cancel",
+		kind,
+	))
+}
@@ -0,0 +1,386 @@
+package prssection
+
+import (
+	"azdo-dash/context"
+	"azdo-dash/data"
+	"azdo-dash/ui/keys"
+	"azdo-dash/ui/section"
+	"azdo-dash/ui/threadview"
+	gocontext "context"
+	"fmt"
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"slices"
+	"strings"
+	"time"
+)
+
+const threadStatusAction = "thread_status"
+
+const threadStatusOption = "status"
+
+var threadStatuses = []string{
+	data.ThreadStatusActive,
+	data.ThreadStatusPending,
+	data.ThreadStatusFixed,
+	data.ThreadStatusWontFix,
+	data.ThreadStatusClosed,
+	data.ThreadStatusByDesign,
+}
+
+// composerHeight is the number of lines of the comment input.
+const composerHeight = 3
+
+type threadsFetchedMsg struct {
+	PrId    int
+	Threads []data.Thread
+}
+
+// threadsKey identifies what the threads viewport currently shows, it is
+// rendered again whenever it changes.
+type threadsKey struct {
+	cursor   int
+	width    int
+	revision int
+}
+
+// threadsView lists the comment threads of a pull request in place of the
+// table. Threads created by the server, e.g. for votes, are left out.
+type threadsView struct {
+	isOpen      bool
+	isLoading   bool
+	pr          data.PullRequestData
+	threads     []data.Thread
+	cursor      int
+	offsets     []int
+	viewport    viewport.Model
+	renderedKey threadsKey
+	revision    int
+	isComposing bool
+	// replyTo is the thread a comment is written for, or 0 for a new thread,
+	// and replyParent the comment of it the reply answers.
+	replyTo     int
+	replyParent int
+	composer    textarea.Model
+	// promptThread is the thread the shown status prompt acts on.
+	promptThread int
+}
+
+var (
+	threadsHeaderStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	composerStyle      = lipgloss.NewStyle().
+				Border(lipgloss.NormalBorder(), true, false, false, false).
+				BorderForeground(lipgloss.Color("8"))
+	threadsHelp = help.New()
+)
+
+func (m *Model) openThreads() tea.Cmd {
+	pr := m.GetCurrPr()
+	if pr == nil {
+		return nil
+	}
+
+	m.threads = threadsView{
+		isOpen:    true,
+		isLoading: true,
+		pr:        *pr,
+		viewport:  viewport.New(0, 0),
+	}
+
+	return m.fetchThreads(*pr)
+}
+
+func (m *Model) closeThreads() {
+	m.threads = threadsView{}
+}
+
+func (m *Model) fetchThreads(pr data.PullRequestData) tea.Cmd {
+	client := m.Ctx.Client
+	task := context.Task{
+		Id:           fmt.Sprintf("fetching_threads_%d_%s", pr.ID, time.Now().String()),
+		StartText:    fmt.Sprintf("Fetching comments of PR #%d", pr.ID),
+		FinishedText: fmt.Sprintf("Comments of PR #%d have been fetched", pr.ID),
+	}
+
+	return m.RunTask(task, func() (tea.Msg, error) {
+		threads, err := client.FetchThreads(gocontext.Background(), pr)
+		if err != nil {
+			return nil, err
+		}
+		return threadsFetchedMsg{PrId: pr.ID, Threads: threads}, nil
+	})
+}
+
+// runThreadTask runs action on the threads of a pull request as a task and
+// fetches the threads again afterwards.
+func (m *Model) runThreadTask(
+	pr data.PullRequestData,
+	task context.Task,
+	action func(ctx gocontext.Context) error,
+) tea.Cmd {
+	client := m.Ctx.Client
+
+	return m.RunTask(task, func() (tea.Msg, error) {
+		ctx := gocontext.Background()
+		if err := action(ctx); err != nil {
+			return nil, err
+		}
+
+		threads, err := client.FetchThreads(ctx, pr)
+		if err != nil {
+			return nil, fmt.Errorf("refreshing the comments of PR #%d: %w", pr.ID, err)
+		}
+		return threadsFetchedMsg{PrId: pr.ID, Threads: threads}, nil
+	})
+}
+
+func (m *Model) onThreadsFetched(msg threadsFetchedMsg) {
+	if !m.threads.isOpen || m.threads.pr.ID != msg.PrId {
+		return
+	}
+
+	threads := make([]data.Thread, 0, len(msg.Threads))
+	for _, thread := range msg.Threads {
+		if !thread.IsSystem() {
+			threads = append(threads, thread)
+		}
+	}
+
+	for i := range m.Prs {
+		if m.Prs[i].ID == msg.PrId {
+			m.Prs[i].UnresolvedThreads = data.UnresolvedThreadCount(threads)
+		}
+	}
+
+	m.threads.threads = threads
+	m.threads.cursor = max(0, min(m.threads.cursor, len(threads)-1))
+	m.threads.isLoading = false
+	m.threads.revision++
+}
+
+func (m *Model) getCurrThread() *data.Thread {
+	cursor := m.threads.cursor
+	if cursor < 0 || cursor >= len(m.threads.threads) {
+		return nil
+	}
+	return &m.threads.threads[cursor]
+}
+
+func (m *Model) updateThreads(msg tea.KeyMsg) tea.Cmd {
+	if m.threads.isComposing {
+		return m.updateComposer(msg)
+	}
+
+	switch {
+	case key.Matches(msg, keys.ThreadKeys.Close):
+		m.closeThreads()
+
+	case key.Matches(msg, keys.Keys.Up):
+		m.threads.cursor = max(0, m.threads.cursor-1)
+
+	case key.Matches(msg, keys.Keys.Down):
+		m.threads.cursor = max(0, min(m.threads.cursor+1, len(m.threads.threads)-1))
+
+	case key.Matches(msg, keys.Keys.PageUp):
+		m.threads.viewport.HalfViewUp()
+
+	case key.Matches(msg, keys.Keys.PageDown):
+		m.threads.viewport.HalfViewDown()
+
+	case key.Matches(msg, keys.ThreadKeys.Reply):
+		if thread := m.getCurrThread(); thread != nil {
+			// Replies answer the comment that started the thread, like in
+			// the web portal.
+			parentCommentId := 0
+			if len(thread.Comments) > 0 {
+				parentCommentId = thread.Comments[0].ID
+			}
+			return m.startComposing(thread.ID, parentCommentId)
+		}
+
+	case key.Matches(msg, keys.ThreadKeys.NewComment):
+		return m.startComposing(0, 0)
+
+	case key.Matches(msg, keys.ThreadKeys.SetStatus):
+		m.promptThreadStatus()
+	}
+
+	return nil
+}
+
+func (m *Model) startComposing(replyTo int, replyParent int) tea.Cmd {
+	composer := textarea.New()
+	composer.ShowLineNumbers = false
+	composer.Prompt = ""
+	composer.Cursor.SetMode(cursor.CursorStatic)
+	composer.CharLimit = 0
+	composer.Placeholder = "Write a new comment in markdown"
+	if replyTo != 0 {
+		composer.Placeholder = "Write a reply in markdown"
+	}
+
+	m.threads.composer = composer
+	m.threads.isComposing = true
+	m.threads.replyTo = replyTo
+	m.threads.replyParent = replyParent
+	return m.threads.composer.Focus()
+}
+
+func (m *Model) updateComposer(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, keys.InputKeys.Cancel):
+		m.threads.isComposing = false
+		return nil
+
+	case key.Matches(msg, keys.InputKeys.Send):
+		content := strings.TrimSpace(m.threads.composer.Value())
+		if content == "" {
+			return nil
+		}
+		m.threads.isComposing = false
+		return m.sendComment(m.threads.replyTo, m.threads.replyParent, content)
+	}
+
+	var cmd tea.Cmd
+	m.threads.composer, cmd = m.threads.composer.Update(msg)
+	return cmd
+}
+
+func (m *Model) sendComment(threadId int, parentCommentId int, content string) tea.Cmd {
+	client := m.Ctx.Client
+	pr := m.threads.pr
+
+	if threadId == 0 {
+		task := context.Task{
+			Id:           fmt.Sprintf("create_thread_pr_%d_%s", pr.ID, time.Now().String()),
+			StartText:    fmt.Sprintf("Commenting on PR #%d", pr.ID),
+			FinishedText: fmt.Sprintf("Commented on PR #%d", pr.ID),
+		}
+		return m.runThreadTask(pr, task, func(ctx gocontext.Context) error {
+			return client.CreateThread(ctx, pr, content)
+		})
+	}
+
+	task := context.Task{
+		Id:           fmt.Sprintf("reply_thread_%d_%s", threadId, time.Now().String()),
+		StartText:    fmt.Sprintf("Replying on PR #%d", pr.ID),
+		FinishedText: fmt.Sprintf("Replied on PR #%d", pr.ID),
+	}
+	return m.runThreadTask(pr, task, func(ctx gocontext.Context) error {
+		return client.ReplyToThread(ctx, pr, threadId, parentCommentId, content)
+	})
+}
+
+func (m *Model) promptThreadStatus() {
+	thread := m.getCurrThread()
+	if thread == nil {
+		return
+	}
+
+	m.threads.promptThread = thread.ID
+	m.ShowPrompt(
+		threadStatusAction,
+		"Change the status of the thread?",
+		section.PromptOption{
+			Key:      "s",
+			Label:    threadStatusOption,
+			Choices:  threadStatuses,
+			Selected: max(0, slices.Index(threadStatuses, thread.Status)),
+		},
+	)
+}
+
+func (m *Model) setThreadStatus() tea.Cmd {
+	client := m.Ctx.Client
+	pr := m.threads.pr
+	threadId := m.threads.promptThread
+	status := m.GetPromptOption(threadStatusOption)
+	task := context.Task{
+		Id:           fmt.Sprintf("thread_status_%d_%s", threadId, time.Now().String()),
+		StartText:    fmt.Sprintf("Setting the thread status to %s", status),
+		FinishedText: fmt.Sprintf("The thread status has been set to %s", status),
+	}
+
+	return m.runThreadTask(pr, task, func(ctx gocontext.Context) error {
+		return client.SetThreadStatus(ctx, pr, threadId, status)
+	})
+}
+
+// syncThreads fits the threads into the screen and scrolls the selected
+// thread into view.
+func (m *Model) syncThreads() {
+	if !m.threads.isOpen {
+		return
+	}
+
+	width := m.Ctx.ScreenWidth
+	// The header and the help line.
+	height := m.Ctx.MainContentHeight - 2
+	if m.IsPromptConfirmationShown {
+		height -= section.PromptHeight
+	}
+	if m.threads.isComposing {
+		height -= composerHeight + composerStyle.GetVerticalFrameSize()
+		m.threads.composer.SetWidth(width)
+		m.threads.composer.SetHeight(composerHeight)
+	}
+	m.threads.viewport.Width = width
+	m.threads.viewport.Height = max(1, height)
+
+	key := threadsKey{cursor: m.threads.cursor, width: width, revision: m.threads.revision}
+	if key == m.threads.renderedKey {
+		return
+	}
+	m.threads.renderedKey = key
+
+	content, offsets := threadview.View(m.threads.threads, m.threads.cursor, width)
+	m.threads.viewport.SetContent(content)
+	m.threads.offsets = offsets
+
+	if m.threads.cursor < len(offsets) {
+		offset := offsets[m.threads.cursor]
+		yOffset := m.threads.viewport.YOffset
+		if offset < yOffset || offset >= yOffset+m.threads.viewport.Height {
+			m.threads.viewport.SetYOffset(offset)
+		}
+	}
+}
+
+func (m Model) viewThreads() string {
+	pr := m.threads.pr
+	header := fmt.Sprintf("Comments of PR #%d %s", pr.ID, pr.Title)
+	if !m.threads.isLoading {
+		header = fmt.Sprintf("%s · %d threads", header, len(m.threads.threads))
+	}
+
+	var body string
+	switch {
+	case m.threads.isLoading:
+		body = emptyStyle.Render("Loading comments...")
+	case len(m.threads.threads) == 0:
+		body = emptyStyle.Render("No comments")
+	default:
+		body = m.threads.viewport.View()
+	}
+
+	parts := []string{
+		lipgloss.NewStyle().MaxWidth(m.Ctx.ScreenWidth).Render(threadsHeaderStyle.Render(header)),
+		lipgloss.NewStyle().Height(m.threads.viewport.Height).Render(body),
+	}
+	if m.IsPromptConfirmationShown {
+		parts = append(parts, m.ViewPrompt())
+	}
+	if m.threads.isComposing {
+		parts = append(parts, composerStyle.Render(m.threads.composer.View()))
+		parts = append(parts, threadsHelp.ShortHelpView([]key.Binding{keys.InputKeys.Send, keys.InputKeys.Cancel}))
+	} else {
+		parts = append(parts, threadsHelp.View(keys.ThreadKeys))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
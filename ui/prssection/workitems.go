@@ -0,0 +1,61 @@
+package prssection
+
+import (
+	"azdo-dash/context"
+	"azdo-dash/data"
+	"azdo-dash/ui/section"
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cli/browser"
+	"time"
+)
+
+const openWorkItemAction = "open_work_item"
+
+const workItemOption = "work item"
+
+// openWorkItem opens the work item linked to the selected pull request in the
+// browser. If several are linked the user picks one in a prompt first.
+func (m *Model) openWorkItem() tea.Cmd {
+	pr := m.GetCurrPr()
+	if pr == nil || len(pr.WorkItems) == 0 {
+		return nil
+	}
+	if len(pr.WorkItems) == 1 {
+		return m.openWorkItemInBrowser(pr.WorkItems[0])
+	}
+
+	choices := make([]string, 0, len(pr.WorkItems))
+	for _, workItem := range pr.WorkItems {
+		choices = append(choices, fmt.Sprintf("#%d %s", workItem.ID, workItem.Title))
+	}
+	m.showPrPrompt(
+		*pr,
+		openWorkItemAction,
+		"Open the work item in the browser?",
+		section.PromptOption{Key: "i", Label: workItemOption, Choices: choices},
+	)
+	return nil
+}
+
+func (m *Model) openSelectedWorkItem() tea.Cmd {
+	pr := m.promptPr
+	selected := m.GetPromptSelection(workItemOption)
+	if selected < 0 || selected >= len(pr.WorkItems) {
+		return nil
+	}
+	return m.openWorkItemInBrowser(pr.WorkItems[selected])
+}
+
+func (m *Model) openWorkItemInBrowser(workItem data.WorkItem) tea.Cmd {
+	url := m.Ctx.Client.WorkItemWebURL(workItem)
+	task := context.Task{
+		Id:           fmt.Sprintf("open_work_item_%d_%s", workItem.ID, time.Now().String()),
+		StartText:    fmt.Sprintf("Opening work item #%d in the browser", workItem.ID),
+		FinishedText: fmt.Sprintf("Work item #%d has been opened in the browser", workItem.ID),
+	}
+
+	return m.RunTask(task, func() (tea.Msg, error) {
+		return nil, browser.OpenURL(url)
+	})
+}
@@ -0,0 +1,254 @@
+package prview
+
+import (
+	"azdo-dash/data"
+	"azdo-dash/ui/markdown"
+	"fmt"
+	"github.com/charmbracelet/lipgloss"
+	"strings"
+)
+
+var (
+	titleStyle   = lipgloss.NewStyle().Bold(true)
+	headingStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	faintStyle   = lipgloss.NewStyle().Faint(true)
+	branchStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+	labelStyle   = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("0")).
+			Background(lipgloss.Color("4")).
+			Padding(0, 1)
+	requiredStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	autoCompleteStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+	conflictStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	workItemIdStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+	newStyle          = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("2"))
+)
+
+var checkStateTexts = map[string]string{
+	data.CheckStatePassed:  lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Render("✓"),
+	data.CheckStateFailed:  lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render("✗"),
+	data.CheckStatePending: lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("●"),
+	data.CheckStateMissing: lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("!"),
+}
+
+var voteTexts = map[int]string{
+	data.VoteApproved:                lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Render("✓ approved"),
+	data.VoteApprovedWithSuggestions: lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("✓ approved with suggestions"),
+	data.VoteNone:                    faintStyle.Render("no vote"),
+	data.VoteWaitingForAuthor:        lipgloss.NewStyle().Foreground(lipgloss.Color("4")).Render("⌛ waiting for author"),
+	data.VoteRejected:                lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render("✗ rejected"),
+}
+
+// View renders the details of a pull request wrapped at width. The iterations
+// after lastSeenIteration are marked as new, unless it is 0.
+func View(pr data.PullRequestData, lastSeenIteration int, width int) string {
+	s := strings.Builder{}
+
+	s.WriteString(titleStyle.Width(width).Render(fmt.Sprintf("#%d %s", pr.ID, pr.Title)))
+	s.WriteString("\n")
+	s.WriteString(faintStyle.Width(width).Render(fmt.Sprintf(
+		"%s · %s · %s",
+		pr.RepositoryName,
+		pr.CreatedBy,
+		pr.CreationDate.Local().Format("2006-01-02 15:04"),
+	)))
+	s.WriteString("\n")
+	s.WriteString(lipgloss.NewStyle().Width(width).Render(fmt.Sprintf(
+		"%s → %s",
+		branchStyle.Render(removePrefix(pr.SourceBranch)),
+		branchStyle.Render(removePrefix(pr.TargetBranch)),
+	)))
+	s.WriteString("\n")
+	if pr.AutoCompleteSetBy != "" {
+		s.WriteString(autoCompleteStyle.Width(width).Render(fmt.Sprintf(
+			"⚡ Auto-complete set by %s (%s)",
+			pr.AutoCompleteSetBy,
+			pr.CompletionOptions.MergeStrategy,
+		)))
+		s.WriteString("\n")
+	}
+
+	if len(pr.Labels) > 0 {
+		labels := make([]string, 0, len(pr.Labels))
+		for _, label := range pr.Labels {
+			labels = append(labels, labelStyle.Render(label))
+		}
+		s.WriteString("\n")
+		s.WriteString(lipgloss.NewStyle().Width(width).Render(strings.Join(labels, " ")))
+		s.WriteString("\n")
+	}
+
+	s.WriteString("\n")
+	s.WriteString(headingStyle.Render("Reviewers"))
+	s.WriteString("\n")
+	s.WriteString(viewReviewers(pr.Reviewers, width))
+
+	s.WriteString("\n")
+	s.WriteString(headingStyle.Render("Checks"))
+	s.WriteString("\n")
+	s.WriteString(viewChecks(pr, width))
+
+	if pr.MergeStatus == data.MergeStatusConflicts {
+		s.WriteString("\n")
+		s.WriteString(headingStyle.Render("Merge conflicts"))
+		s.WriteString("\n")
+		if pr.HasDetails {
+			s.WriteString(viewConflicts(pr.Conflicts, width))
+		} else {
+			s.WriteString(viewLoading("the conflicting files"))
+		}
+	}
+
+	s.WriteString("\n")
+	s.WriteString(headingStyle.Render("Work items"))
+	s.WriteString("\n")
+	if pr.HasIndicators {
+		s.WriteString(viewWorkItems(pr.WorkItems, width))
+	} else {
+		s.WriteString(viewLoading("work items"))
+	}
+
+	s.WriteString("\n")
+	s.WriteString(headingStyle.Render("Iterations"))
+	s.WriteString("\n")
+	if pr.HasDetails {
+		s.WriteString(viewIterations(pr.Iterations, lastSeenIteration, width))
+	} else {
+		s.WriteString(viewLoading("iterations"))
+	}
+
+	s.WriteString("\n")
+	s.WriteString(headingStyle.Render("Description"))
+	s.WriteString("\n")
+	if strings.TrimSpace(pr.Description) == "" {
+		s.WriteString(faintStyle.Render("No description provided."))
+		s.WriteString("\n")
+	} else {
+		s.WriteString(markdown.Render(pr.Description, width))
+	}
+
+	return s.String()
+}
+
+func viewReviewers(reviewers []data.Reviewer, width int) string {
+	if len(reviewers) == 0 {
+		return faintStyle.Render("No reviewers.") + "\n"
+	}
+
+	s := strings.Builder{}
+	for _, reviewer := range reviewers {
+		vote, ok := voteTexts[reviewer.Vote]
+		if !ok {
+			vote = voteTexts[data.VoteNone]
+		}
+
+		name := reviewer.DisplayName
+		if reviewer.IsRequired {
+			name += requiredStyle.Render(" (required)")
+		}
+		s.WriteString(lipgloss.NewStyle().Width(width).Render(fmt.Sprintf("%s  %s", name, vote)))
+		s.WriteString("\n")
+	}
+
+	return s.String()
+}
+
+func viewChecks(pr data.PullRequestData, width int) string {
+	if !pr.HasIndicators {
+		return viewLoading("checks")
+	}
+	if pr.ChecksState == data.CheckStateUnknown {
+		return faintStyle.Render("Checks could not be loaded.") + "\n"
+	}
+	if len(pr.Checks) == 0 {
+		return faintStyle.Render("No checks.") + "\n"
+	}
+
+	s := strings.Builder{}
+	for _, check := range pr.Checks {
+		text := fmt.Sprintf("%s %s", checkStateTexts[check.State], check.Name)
+		if check.IsRequired {
+			text += requiredStyle.Render(" (required)")
+		}
+		if check.Description != "" {
+			text += faintStyle.Render(" · " + check.Description)
+		}
+		s.WriteString(lipgloss.NewStyle().Width(width).Render(text))
+		s.WriteString("\n")
+	}
+
+	return s.String()
+}
+
+// viewLoading tells that what is shown below a heading is still fetched.
+func viewLoading(what string) string {
+	return faintStyle.Render(fmt.Sprintf("Loading %s...", what)) + "\n"
+}
+
+func viewConflicts(conflicts []string, width int) string {
+	if len(conflicts) == 0 {
+		return faintStyle.Render("The conflicting files could not be loaded.") + "\n"
+	}
+
+	s := strings.Builder{}
+	for _, path := range conflicts {
+		s.WriteString(conflictStyle.Width(width).Render("✗ " + path))
+		s.WriteString("\n")
+	}
+
+	return s.String()
+}
+
+func viewWorkItems(workItems []data.WorkItem, width int) string {
+	if workItems == nil {
+		return faintStyle.Render("Work items could not be loaded.") + "\n"
+	}
+	if len(workItems) == 0 {
+		return faintStyle.Render("No linked work items.") + "\n"
+	}
+
+	s := strings.Builder{}
+	for _, workItem := range workItems {
+		s.WriteString(lipgloss.NewStyle().Width(width).Render(fmt.Sprintf(
+			"%s %s %s",
+			workItemIdStyle.Render(fmt.Sprintf("#%d", workItem.ID)),
+			workItem.Title,
+			faintStyle.Render(fmt.Sprintf("· %s · %s", workItem.Type, workItem.State)),
+		)))
+		s.WriteString("\n")
+	}
+
+	return s.String()
+}
+
+// viewIterations lists the iterations newest first.
+func viewIterations(iterations []data.Iteration, lastSeenIteration int, width int) string {
+	if iterations == nil {
+		return faintStyle.Render("Iterations could not be loaded.") + "\n"
+	}
+
+	s := strings.Builder{}
+	for i := len(iterations) - 1; i >= 0; i-- {
+		iteration := iterations[i]
+		text := fmt.Sprintf("%d", iteration.ID)
+		if iteration.Description != "" {
+			text += " " + iteration.Description
+		}
+		text += faintStyle.Render(fmt.Sprintf(
+			" · %s · %s",
+			iteration.Author,
+			iteration.CreatedDate.Local().Format("2006-01-02 15:04"),
+		))
+		if lastSeenIteration != 0 && iteration.ID > lastSeenIteration {
+			text += newStyle.Render(" new")
+		}
+		s.WriteString(lipgloss.NewStyle().Width(width).Render(text))
+		s.WriteString("\n")
+	}
+
+	return s.String()
+}
+
+func removePrefix(refName string) string {
+	return strings.TrimPrefix(refName, "refs/heads/")
+}
@@ -0,0 +1,29 @@
+package section
+
+import (
+	"azdo-dash/context"
+	"azdo-dash/ui/identitypicker"
+	gocontext "context"
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"time"
+)
+
+// SearchIdentities finds the identities matching query in a task, its
+// identitypicker.IdentitiesFoundMsg is handed to the section.
+func (m *Model) SearchIdentities(query string) tea.Cmd {
+	client := m.Ctx.Client
+	task := context.Task{
+		Id:           fmt.Sprintf("search_identities_%s", time.Now().String()),
+		StartText:    fmt.Sprintf(`Searching for "%s"`, query),
+		FinishedText: fmt.Sprintf(`Searched for "%s"`, query),
+	}
+
+	return m.RunTask(task, func() (tea.Msg, error) {
+		identities, err := client.SearchIdentities(gocontext.Background(), query)
+		if err != nil {
+			return nil, fmt.Errorf("searching for %q: %w", query, err)
+		}
+		return identitypicker.IdentitiesFoundMsg{Query: query, Identities: identities}, nil
+	})
+}
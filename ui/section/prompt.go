@@ -0,0 +1,138 @@
+package section
+
+import (
+	"azdo-dash/ui/keys"
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"strings"
+)
+
+// PromptOption is a setting of a confirmation prompt that is cycled through
+// its choices with Key.
+type PromptOption struct {
+	Key      string
+	Label    string
+	Choices  []string
+	Selected int
+}
+
+func (o PromptOption) Value() string {
+	if o.Selected < 0 || o.Selected >= len(o.Choices) {
+		return ""
+	}
+	return o.Choices[o.Selected]
+}
+
+var YesNo = []string{"yes", "no"}
+
+type PromptResult int
+
+const (
+	PromptPending PromptResult = iota
+	PromptConfirmed
+	PromptCancelled
+)
+
+var (
+	promptStyle = lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder(), true, false, false, false).
+			BorderForeground(lipgloss.Color("8")).
+			PaddingLeft(1)
+	promptTextStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	promptKeyStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+	promptValueStyle  = lipgloss.NewStyle().Bold(true)
+	promptFooterStyle = lipgloss.NewStyle().Faint(true)
+)
+
+// PromptHeight is the number of lines a prompt takes up below the table.
+const PromptHeight = 3
+
+// ShowPrompt asks the user to confirm action. The options can be changed
+// before confirming.
+func (m *Model) ShowPrompt(action string, text string, options ...PromptOption) {
+	m.IsPromptConfirmationShown = true
+	m.PromptConfirmationAction = action
+	m.PromptText = text
+	m.PromptOptions = options
+}
+
+func (m *Model) HidePrompt() {
+	m.IsPromptConfirmationShown = false
+	m.PromptConfirmationAction = ""
+	m.PromptText = ""
+	m.PromptOptions = nil
+}
+
+// GetPromptOption returns the selected choice of the option with label.
+func (m *Model) GetPromptOption(label string) string {
+	for _, option := range m.PromptOptions {
+		if option.Label == label {
+			return option.Value()
+		}
+	}
+	return ""
+}
+
+// GetPromptSelection returns the index of the selected choice of the option
+// with label, or -1 if there is no such option.
+func (m *Model) GetPromptSelection(label string) int {
+	for _, option := range m.PromptOptions {
+		if option.Label == label {
+			return option.Selected
+		}
+	}
+	return -1
+}
+
+// UpdatePrompt handles a key while the prompt is shown. The prompt is hidden
+// once it was confirmed or cancelled, PromptConfirmationAction still tells
+// which action was confirmed until the next prompt is shown.
+func (m *Model) UpdatePrompt(msg tea.KeyMsg) PromptResult {
+	switch {
+	case key.Matches(msg, keys.PromptKeys.Confirm):
+		action := m.PromptConfirmationAction
+		options := m.PromptOptions
+		m.HidePrompt()
+		m.PromptConfirmationAction = action
+		m.PromptOptions = options
+		return PromptConfirmed
+
+	case key.Matches(msg, keys.PromptKeys.Cancel):
+		m.HidePrompt()
+		return PromptCancelled
+	}
+
+	for i, option := range m.PromptOptions {
+		if msg.String() == option.Key && len(option.Choices) > 0 {
+			m.PromptOptions[i].Selected = (option.Selected + 1) % len(option.Choices)
+		}
+	}
+
+	return PromptPending
+}
+
+func (m *Model) ViewPrompt() string {
+	if !m.IsPromptConfirmationShown {
+		return ""
+	}
+
+	options := make([]string, 0, len(m.PromptOptions))
+	for _, option := range m.PromptOptions {
+		options = append(options, fmt.Sprintf(
+			"%s %s: %s",
+			promptKeyStyle.Render("["+option.Key+"]"),
+			option.Label,
+			promptValueStyle.Render(option.Value()),
+		))
+	}
+
+	width := max(0, m.Ctx.MainContentWidth-promptStyle.GetHorizontalFrameSize())
+	line := lipgloss.NewStyle().MaxWidth(width)
+	return promptStyle.Render(lipgloss.JoinVertical(
+		lipgloss.Left,
+		line.Render(promptTextStyle.Render(m.PromptText)+"  "+strings.Join(options, "  ")),
+		line.Render(promptFooterStyle.Render("y/enter confirm • n/esc cancel")),
+	))
+}
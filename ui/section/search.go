@@ -0,0 +1,56 @@
+package section
+
+import (
+	"azdo-dash/ui/keys"
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var searchStyle = lipgloss.NewStyle().PaddingLeft(1)
+
+// StartSearch opens the input for SearchValue below the table. The section
+// filters its rows while the value is typed.
+func (m *Model) StartSearch() {
+	input := textinput.New()
+	input.Prompt = "/"
+	input.Placeholder = "text or column:value"
+	input.Cursor.SetMode(cursor.CursorStatic)
+	input.SetValue(m.SearchValue)
+	input.CursorEnd()
+	input.Focus()
+
+	m.searchInput = input
+	m.IsSearching = true
+}
+
+// UpdateSearch handles a key while searching. Enter keeps the search value,
+// cancelling clears it.
+func (m *Model) UpdateSearch(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, keys.InputKeys.Submit):
+		m.IsSearching = false
+		return nil
+
+	case key.Matches(msg, keys.InputKeys.Cancel):
+		m.IsSearching = false
+		m.SearchValue = ""
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.searchInput, cmd = m.searchInput.Update(msg)
+	m.SearchValue = m.searchInput.Value()
+	return cmd
+}
+
+func (m *Model) ViewSearch() string {
+	if !m.IsSearching {
+		return ""
+	}
+
+	m.searchInput.Width = max(0, m.Ctx.MainContentWidth-searchStyle.GetHorizontalFrameSize()-len(m.searchInput.Prompt)-1)
+	return searchStyle.Render(m.searchInput.View())
+}
@@ -1,9 +1,13 @@
 package section
 
 import (
+	"azdo-dash/config"
+	"azdo-dash/constants"
 	"azdo-dash/context"
+	gocontext "context"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"time"
 )
@@ -11,6 +15,7 @@ import (
 type Model struct {
 	Id                        int
 	Ctx                       *context.ProgramContext
+	Config                    config.SectionConfig
 	Spinner                   spinner.Model
 	IsSearching               bool
 	SearchValue               string
@@ -20,14 +25,20 @@ type Model struct {
 	PluralForm                string
 	Columns                   []table.Column
 	TotalCount                int
+	IsLoading                 bool
 	IsPromptConfirmationShown bool
 	PromptConfirmationAction  string
+	PromptText                string
+	PromptOptions             []PromptOption
 	LastFetchTaskId           string
+	searchInput               textinput.Model
+	cancelFetch               gocontext.CancelFunc
 }
 
 func NewModel(
 	id int,
 	ctx *context.ProgramContext,
+	cfg config.SectionConfig,
 	sType string,
 	lastUpdated time.Time,
 ) Model {
@@ -35,6 +46,7 @@ func NewModel(
 		Id:      id,
 		Type:    sType,
 		Ctx:     ctx,
+		Config:  cfg,
 		Spinner: spinner.Model{Spinner: spinner.Dot},
 	}
 
@@ -44,16 +56,29 @@ func NewModel(
 type Section interface {
 	Identifier
 	Component
+	Fetcher
 }
 
 type Identifier interface {
 	GetId() int
 	GetType() string
+	GetTitle() string
+	GetTotalCount() int
+	GetIsLoading() bool
 }
 
 type Component interface {
 	Update(msg tea.Msg) (Section, tea.Cmd)
 	View() string
+	// IsCapturingKeys reports whether the section wants all keys, e.g.
+	// while it shows a prompt.
+	IsCapturingKeys() bool
+}
+
+type Fetcher interface {
+	FetchNextPageSectionRows() []tea.Cmd
+	ResetRows()
+	CancelFetch()
 }
 
 func (m *Model) GetId() int {
@@ -63,3 +88,62 @@ func (m *Model) GetId() int {
 func (m *Model) GetType() string {
 	return m.Type
 }
+
+func (m *Model) GetTitle() string {
+	return m.Config.Title
+}
+
+func (m *Model) GetTotalCount() int {
+	return m.TotalCount
+}
+
+func (m *Model) GetIsLoading() bool {
+	return m.IsLoading
+}
+
+// NewFetchContext cancels the section's previous fetch and returns the
+// context the next one runs in.
+func (m *Model) NewFetchContext() gocontext.Context {
+	m.CancelFetch()
+
+	ctx, cancel := gocontext.WithCancel(gocontext.Background())
+	m.cancelFetch = cancel
+	return ctx
+}
+
+func (m *Model) CancelFetch() {
+	if m.cancelFetch != nil {
+		m.cancelFetch()
+		m.cancelFetch = nil
+	}
+}
+
+// RunTask starts task and runs fn in the background. The message returned by
+// fn is delivered to the section once the task finished, unless it is a
+// constants.TaskFinishedTextMsg describing the result.
+func (m *Model) RunTask(task context.Task, fn func() (tea.Msg, error)) tea.Cmd {
+	task.State = context.TaskStart
+	startCmd := m.Ctx.StartTask(task)
+
+	id := m.Id
+	sType := m.Type
+	return tea.Batch(startCmd, func() tea.Msg {
+		msg, err := fn()
+		finished := constants.TaskFinishedMsg{
+			SectionId:   id,
+			SectionType: sType,
+			TaskId:      task.Id,
+			Err:         err,
+			Msg:         msg,
+		}
+		if text, ok := msg.(constants.TaskFinishedTextMsg); ok {
+			finished.Msg = nil
+			finished.FinishedText = string(text)
+		}
+		return finished
+	})
+}
+
+func (m *Model) IsCapturingKeys() bool {
+	return m.IsPromptConfirmationShown || m.IsSearching
+}
@@ -0,0 +1,94 @@
+package section
+
+import (
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+	"slices"
+)
+
+// cellPadding is the horizontal padding the table adds around every cell.
+const cellPadding = 2
+
+// TableHeaderHeight is the height of the header row including its border.
+const TableHeaderHeight = 2
+
+var TableStyles = table.Styles{
+	Header: lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("205")).
+		Padding(0, 1).
+		Border(lipgloss.NormalBorder(), false, false, true, false).
+		BorderForeground(lipgloss.Color("8")),
+	Cell: lipgloss.NewStyle().Padding(0, 1),
+	Selected: lipgloss.NewStyle().
+		Bold(true).
+		Background(lipgloss.Color("237")),
+}
+
+// ColumnLayout sizes a column of a section's table. Columns are at least
+// Width wide and share the remaining width of the screen by their Grow
+// factor. Screens too narrow for all columns hide the columns with the
+// highest Priority first.
+type ColumnLayout struct {
+	Title    string
+	Width    int
+	Grow     int
+	Priority int
+}
+
+// TableColumns lays out the columns that fit on a screen of width and returns
+// them with the indices of the shown columns, the cells of rows are picked
+// by them. The table is never wider than width.
+func TableColumns(columns []ColumnLayout, width int) ([]table.Column, []int) {
+	shown := make([]int, 0, len(columns))
+	for i := range columns {
+		shown = append(shown, i)
+	}
+	for len(shown) > 1 && columnsWidth(columns, shown) > width {
+		// Of equal priorities the rightmost column goes first.
+		hidden := 0
+		for i, index := range shown {
+			if columns[index].Priority >= columns[shown[hidden]].Priority {
+				hidden = i
+			}
+		}
+		shown = slices.Delete(shown, hidden, hidden+1)
+	}
+
+	extraWidth := max(0, width-columnsWidth(columns, shown))
+	totalGrow := 0
+	for _, index := range shown {
+		totalGrow += columns[index].Grow
+	}
+	distributed := 0
+	grown := 0
+	tableColumns := make([]table.Column, 0, len(shown))
+	for _, index := range shown {
+		col := columns[index]
+		colWidth := col.Width
+		if col.Grow > 0 {
+			// Hand out the share of the columns grown so far and this one,
+			// so rounding never adds up to more than the extra width.
+			grown += col.Grow
+			share := extraWidth*grown/totalGrow - distributed
+			distributed += share
+			colWidth += share
+		}
+		tableColumns = append(tableColumns, table.Column{Title: col.Title, Width: colWidth})
+	}
+
+	// A single column left is cut to the screen.
+	if len(tableColumns) == 1 {
+		tableColumns[0].Width = max(1, min(tableColumns[0].Width, width-cellPadding))
+	}
+
+	return tableColumns, shown
+}
+
+func columnsWidth(columns []ColumnLayout, shown []int) int {
+	width := 0
+	for _, index := range shown {
+		width += columns[index].Width + cellPadding
+	}
+	return width
+}
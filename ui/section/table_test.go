@@ -0,0 +1,53 @@
+package section
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTableColumns(t *testing.T) {
+	columns := []ColumnLayout{
+		{Title: "ID", Width: 5, Priority: 1},
+		{Title: "Title", Width: 20, Grow: 3},
+		{Title: "Author", Width: 10, Grow: 1, Priority: 2},
+		{Title: "Repo", Width: 8, Priority: 2},
+	}
+
+	tests := []struct {
+		name      string
+		width     int
+		wantShown []int
+		want      []int
+	}{
+		{"exact fit", 51, []int{0, 1, 2, 3}, []int{5, 20, 10, 8}},
+		{"wide screen", 91, []int{0, 1, 2, 3}, []int{5, 50, 20, 8}},
+		{"uneven extra width", 54, []int{0, 1, 2, 3}, []int{5, 22, 11, 8}},
+		{"rightmost of equal priorities hidden first", 45, []int{0, 1, 2}, []int{5, 23, 11}},
+		{"highest priority hidden first", 30, []int{0, 1}, []int{5, 21}},
+		{"only the column of the first priority", 25, []int{1}, []int{23}},
+		{"narrower than a column", 10, []int{1}, []int{8}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, shown := TableColumns(columns, test.width)
+
+			if !slices.Equal(shown, test.wantShown) {
+				t.Fatalf("shown columns = %v, want %v", shown, test.wantShown)
+			}
+			total := 0
+			for i, col := range got {
+				total += col.Width + cellPadding
+				if col.Title != columns[shown[i]].Title {
+					t.Errorf("column %d = %q, want %q", i, col.Title, columns[shown[i]].Title)
+				}
+				if col.Width != test.want[i] {
+					t.Errorf("%s is %d wide, want %d", col.Title, col.Width, test.want[i])
+				}
+			}
+			if total > test.width {
+				t.Errorf("columns are %d wide, want at most %d", total, test.width)
+			}
+		})
+	}
+}
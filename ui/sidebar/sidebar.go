@@ -0,0 +1,68 @@
+package sidebar
+
+import (
+	"azdo-dash/ui/keys"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var style = lipgloss.NewStyle().
+	Border(lipgloss.NormalBorder(), false, false, false, true).
+	BorderForeground(lipgloss.Color("8")).
+	PaddingLeft(1)
+
+// Model is a scrollable pane shown next to the main content.
+type Model struct {
+	viewport viewport.Model
+	width    int
+	height   int
+}
+
+func NewModel() Model {
+	return Model{viewport: viewport.New(0, 0)}
+}
+
+// SetSize sets the outer size of the pane, including its border.
+func (m *Model) SetSize(width int, height int) {
+	m.width = width
+	m.height = height
+	m.viewport.Width = m.GetContentWidth()
+	m.viewport.Height = height
+}
+
+// GetContentWidth returns the width left for the content.
+func (m Model) GetContentWidth() int {
+	return max(0, m.width-style.GetHorizontalFrameSize())
+}
+
+func (m *Model) SetContent(content string) {
+	m.viewport.SetContent(content)
+}
+
+func (m *Model) GotoTop() {
+	m.viewport.GotoTop()
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.Keys.PreviewDown):
+			m.viewport.LineDown(1)
+		case key.Matches(msg, keys.Keys.PreviewUp):
+			m.viewport.LineUp(1)
+		}
+	}
+
+	return m, nil
+}
+
+func (m Model) View() string {
+	return style.
+		Width(max(0, m.width-style.GetHorizontalBorderSize())).
+		Height(m.height).
+		MaxHeight(m.height).
+		Render(m.viewport.View())
+}
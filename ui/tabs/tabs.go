@@ -0,0 +1,50 @@
+package tabs
+
+import (
+	"fmt"
+	"github.com/charmbracelet/lipgloss"
+	"strings"
+)
+
+type Tab struct {
+	Title     string
+	Count     int
+	IsLoading bool
+}
+
+var (
+	activeTabStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("205")).
+			Padding(0, 1)
+	inactiveTabStyle = lipgloss.NewStyle().
+				Faint(true).
+				Padding(0, 1)
+	separator = lipgloss.NewStyle().Faint(true).Render("│")
+	tabsStyle = lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder(), false, false, true, false).
+			BorderForeground(lipgloss.Color("8"))
+)
+
+func View(tabs []Tab, currSectionIndex int, width int) string {
+	renderedTabs := make([]string, 0, len(tabs))
+	for i, tab := range tabs {
+		count := fmt.Sprintf("(%d)", tab.Count)
+		if tab.IsLoading {
+			count = "(…)"
+		}
+
+		title := fmt.Sprintf("%s %s", tab.Title, count)
+		if i == currSectionIndex {
+			renderedTabs = append(renderedTabs, activeTabStyle.Render(title))
+		} else {
+			renderedTabs = append(renderedTabs, inactiveTabStyle.Render(title))
+		}
+	}
+
+	style := tabsStyle
+	if width > 0 {
+		style = style.Width(width)
+	}
+	return style.Render(strings.Join(renderedTabs, separator))
+}
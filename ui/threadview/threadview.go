@@ -0,0 +1,110 @@
+package threadview
+
+import (
+	"azdo-dash/data"
+	"azdo-dash/ui/markdown"
+	"fmt"
+	"github.com/charmbracelet/lipgloss"
+	"strings"
+)
+
+var (
+	locationStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+	authorStyle   = lipgloss.NewStyle().Bold(true)
+	faintStyle    = lipgloss.NewStyle().Faint(true)
+	selectedStyle = lipgloss.NewStyle().
+			Border(lipgloss.ThickBorder(), false, false, false, true).
+			BorderForeground(lipgloss.Color("205")).
+			PaddingLeft(1)
+	unselectedStyle = lipgloss.NewStyle().
+			Border(lipgloss.HiddenBorder(), false, false, false, true).
+			PaddingLeft(1)
+)
+
+var statusTexts = map[string]string{
+	data.ThreadStatusActive:   lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Render("● active"),
+	data.ThreadStatusPending:  lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("● pending"),
+	data.ThreadStatusFixed:    lipgloss.NewStyle().Foreground(lipgloss.Color("6")).Render("✓ fixed"),
+	data.ThreadStatusWontFix:  faintStyle.Render("✗ won't fix"),
+	data.ThreadStatusClosed:   faintStyle.Render("✓ closed"),
+	data.ThreadStatusByDesign: faintStyle.Render("✓ by design"),
+}
+
+// replyIndent is how far replies are indented below the comment they answer.
+const replyIndent = 2
+
+// View renders threads wrapped at width with the thread at cursor
+// highlighted. It also returns the line each thread starts at, so the caller
+// can scroll to the selected one.
+func View(threads []data.Thread, cursor int, width int) (string, []int) {
+	s := strings.Builder{}
+	offsets := make([]int, 0, len(threads))
+	line := 0
+
+	for i, thread := range threads {
+		style := unselectedStyle
+		if i == cursor {
+			style = selectedStyle
+		}
+
+		rendered := style.Render(viewThread(thread, width-style.GetHorizontalFrameSize()))
+		offsets = append(offsets, line)
+		s.WriteString(rendered)
+		s.WriteString("\n\n")
+		line += lipgloss.Height(rendered) + 1
+	}
+
+	return s.String(), offsets
+}
+
+func viewThread(thread data.Thread, width int) string {
+	location := "General"
+	if thread.FilePath != "" {
+		location = thread.FilePath
+		if thread.Line > 0 {
+			location = fmt.Sprintf("%s:%d", location, thread.Line)
+		}
+	}
+
+	status, ok := statusTexts[thread.Status]
+	if !ok {
+		status = faintStyle.Render(thread.Status)
+	}
+
+	lines := []string{
+		lipgloss.NewStyle().MaxWidth(width).Render(
+			fmt.Sprintf("%s  %s", status, locationStyle.Render(location)),
+		),
+	}
+
+	depths := map[int]int{}
+	for _, comment := range thread.Comments {
+		depth := 0
+		if parentDepth, ok := depths[comment.ParentCommentID]; ok && comment.ParentCommentID != 0 {
+			depth = parentDepth + 1
+		}
+		depths[comment.ID] = depth
+
+		lines = append(lines, viewComment(comment, depth*replyIndent, width))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+func viewComment(comment data.Comment, indent int, width int) string {
+	contentWidth := max(1, width-indent)
+	header := fmt.Sprintf(
+		"%s %s",
+		authorStyle.Render(comment.Author),
+		faintStyle.Render("· "+comment.PublishedDate.Local().Format("2006-01-02 15:04")),
+	)
+	content := strings.Trim(markdown.Render(comment.Content, contentWidth), "\n")
+
+	return lipgloss.NewStyle().PaddingLeft(indent).Render(
+		lipgloss.JoinVertical(
+			lipgloss.Left,
+			lipgloss.NewStyle().MaxWidth(contentWidth).Render(header),
+			content,
+		),
+	)
+}
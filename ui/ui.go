@@ -4,8 +4,16 @@ import (
 	"azdo-dash/config"
 	"azdo-dash/constants"
 	"azdo-dash/context"
+	"azdo-dash/data"
+	"azdo-dash/state"
+	"azdo-dash/ui/keys"
 	"azdo-dash/ui/prssection"
 	"azdo-dash/ui/section"
+	"azdo-dash/ui/tabs"
+	"azdo-dash/ui/workitemssection"
+	gocontext "context"
+	"fmt"
+	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
@@ -20,22 +28,25 @@ type ErrMsg error
 
 type initMsg struct {
 	Config config.Config
+	Client *data.Client
+	User   data.User
+	Visits *state.Visits
 }
 
-var quitKeys = key.NewBinding(
-	key.WithKeys("q", "esc", "ctrl+c"),
-	key.WithHelp("", "press q to quit"),
-)
+// tabsHeight is the height of the tab bar including its bottom border.
+const tabsHeight = 2
 
 type Model struct {
 	items       []string
 	quitting    bool
 	err         error
 	configPath  string
-	ctx         context.ProgramContext
-	prSection   section.Section
+	ctx         *context.ProgramContext
+	sections    []section.Section
+	currSection int
 	tasks       map[string]context.Task
 	taskSpinner spinner.Model
+	help        help.Model
 }
 
 func NewModel(configPath string) Model {
@@ -45,13 +56,18 @@ func NewModel(configPath string) Model {
 		configPath:  configPath,
 		tasks:       map[string]context.Task{},
 		taskSpinner: taskSpinner,
+		help:        help.New(),
 	}
-	m.ctx = context.ProgramContext{
+	m.ctx = &context.ProgramContext{
 		ConfigPath: configPath,
 		StartTask: func(task context.Task) tea.Cmd {
 			log.Debug("Starting task", "id", task.Id)
 			task.StartTime = time.Now()
+			isSpinning := hasRunningTasks(m.tasks)
 			m.tasks[task.Id] = task
+			if isSpinning {
+				return nil
+			}
 			return m.taskSpinner.Tick
 		},
 	}
@@ -60,7 +76,7 @@ func NewModel(configPath string) Model {
 }
 
 func (m *Model) initScreen() tea.Msg {
-	showError := func(err error) {
+	showError := func(prefix string, message string, err error) {
 		styles := log.DefaultStyles()
 		styles.Key = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("1")).
@@ -71,12 +87,12 @@ func (m *Model) initScreen() tea.Msg {
 		logger.SetStyles(styles)
 		logger.SetTimeFormat(time.RFC3339)
 		logger.SetReportTimestamp(true)
-		logger.SetPrefix("Reading config file")
+		logger.SetPrefix(prefix)
 		logger.SetReportCaller(true)
 
 		logger.
 			Fatal(
-				"failed parsing config file",
+				message,
 				"location",
 				m.configPath,
 				"err",
@@ -86,11 +102,37 @@ func (m *Model) initScreen() tea.Msg {
 
 	cfg, err := config.ParseConfig(m.ctx.ConfigPath)
 	if err != nil {
-		showError(err)
+		showError("Reading config file", "failed parsing config file", err)
 		return initMsg{Config: cfg}
 	}
 
-	return initMsg{Config: cfg}
+	client := data.NewClient(
+		cfg.BaseURL,
+		cfg.OrgName,
+		cfg.APIVersion,
+		data.PersonalAccessTokenAuth{Token: cfg.PersonalAccessToken},
+	)
+
+	user, err := client.FetchCurrentUser(gocontext.Background())
+	if err != nil {
+		return ErrMsg(fmt.Errorf("failed resolving the user of the personal access token: %w", err))
+	}
+
+	// Without the groups only the groups the user voted for count towards
+	// required reviews.
+	user.Groups, err = client.FetchGroups(gocontext.Background(), user)
+	if err != nil {
+		log.Error("Failed resolving the groups of the user", "err", err)
+	}
+
+	// Without the stored visits everything counts as unseen, which is no
+	// reason not to start.
+	visits, err := state.LoadVisits()
+	if err != nil {
+		log.Error("Failed loading the visits of pull requests", "err", err)
+	}
+
+	return initMsg{Config: cfg, Client: client, User: user, Visits: visits}
 }
 
 func (m Model) Init() tea.Cmd {
@@ -107,9 +149,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case initMsg:
 		m.ctx.Config = &msg.Config
-
-		prSection, fetchSectionsCmd := m.fetchAllViewSections()
-		m.setCurrentViewSections(prSection)
+		m.ctx.Client = msg.Client
+		m.ctx.User = &msg.User
+		m.ctx.Visits = msg.Visits
+		m.ctx.PreviewOpen = msg.Config.Defaults.Preview.Open
+		m.syncMainContentSize()
+
+		sections, fetchSectionsCmd := m.fetchAllViewSections()
+		m.setCurrentViewSections(sections)
 		cmds = append(cmds, fetchSectionsCmd)
 
 	case constants.TaskFinishedMsg:
@@ -123,23 +170,81 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else {
 				task.State = context.TaskFinished
 			}
+			if msg.FinishedText != "" {
+				task.FinishedText = msg.FinishedText
+			}
 			now := time.Now()
 			task.FinishedTime = &now
 			m.tasks[msg.TaskId] = task
-			cmd = tea.Tick(2*time.Second, func(t time.Time) tea.Msg {
+			clearAfter := 2 * time.Second
+			if task.State == context.TaskError {
+				clearAfter = 10 * time.Second
+			}
+			cmd = tea.Tick(clearAfter, func(t time.Time) tea.Msg {
 				return constants.ClearTaskMsg{TaskId: msg.TaskId}
 			})
 
-			m.updateSection(msg.SectionId, msg.SectionType, msg.Msg)
+			cmds = append(cmds, m.updateSection(msg.SectionId, msg.SectionType, msg.Msg))
 		}
 
 	case tea.KeyMsg:
-		if key.Matches(msg, quitKeys) {
+		if currSection := m.getCurrSection(); currSection != nil && currSection.IsCapturingKeys() {
+			return m, m.updateCurrentSection(msg)
+		}
+		if key.Matches(msg, keys.Keys.Quit) {
 			m.quitting = true
+			for _, section := range m.getCurrentViewSections() {
+				section.CancelFetch()
+			}
 			return m, tea.Quit
 
 		}
+		if key.Matches(msg, keys.Keys.Refresh) {
+			currSection := m.getCurrSection()
+			if currSection == nil {
+				return m, nil
+			}
+			currSection.ResetRows()
+			return m, tea.Batch(currSection.FetchNextPageSectionRows()...)
+		}
+		// Sections of different types have help of different heights.
+		if key.Matches(msg, keys.Keys.NextSection) {
+			m.setCurrSection(m.currSection + 1)
+			return m, m.onMainContentResize()
+		}
+		if key.Matches(msg, keys.Keys.PrevSection) {
+			m.setCurrSection(m.currSection - 1)
+			return m, m.onMainContentResize()
+		}
+		if key.Matches(msg, keys.Keys.Help) {
+			m.help.ShowAll = !m.help.ShowAll
+			return m, m.onMainContentResize()
+		}
+		if key.Matches(msg, keys.Keys.TogglePreview) {
+			m.ctx.PreviewOpen = !m.ctx.PreviewOpen
+			return m, m.onMainContentResize()
+		}
+		return m, m.updateCurrentSection(msg)
+	case constants.SectionMsg:
+		return m, m.updateSection(msg.SectionId, msg.SectionType, msg.Msg)
+
+	case constants.ClearTaskMsg:
+		delete(m.tasks, msg.TaskId)
 		return m, nil
+
+	case spinner.TickMsg:
+		if !hasRunningTasks(m.tasks) {
+			return m, nil
+		}
+		m.taskSpinner, cmd = m.taskSpinner.Update(msg)
+		return m, cmd
+
+	case tea.WindowSizeMsg:
+		m.ctx.ScreenWidth = msg.Width
+		m.ctx.ScreenHeight = msg.Height
+		m.syncMainContentSize()
+		return m, m.updateAllSections(msg)
+
 	case ErrMsg:
 		m.err = msg
 		return m, nil
@@ -149,39 +254,158 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
-	sectionCmd := m.updateCurrentSection(msg)
+	sectionCmd := m.updateAllSections(msg)
 	cmds = append(cmds, cmd, sectionCmd)
 	return m, tea.Batch(cmds...)
 }
 
 func (m Model) View() string {
+	if m.err != nil {
+		return taskErrorStyle.Render(fmt.Sprintf("✗ %s", m.err)) + "\n" +
+			taskStyle.Render("Press q to quit") + "\n"
+	}
 	if m.ctx.Config == nil {
 		return "Reading config...\n"
 	}
 
 	s := strings.Builder{}
+	s.WriteString(m.viewTabs())
 	s.WriteString("\n")
 	currSection := m.getCurrSection()
 	mainContent := ""
 	if currSection != nil {
 		mainContent = lipgloss.JoinHorizontal(
 			lipgloss.Top,
-			m.getCurrSection().View(),
+			currSection.View(),
 		)
 	} else {
 		mainContent = "No sections defined..."
 	}
-	s.WriteString(mainContent)
+	s.WriteString(lipgloss.NewStyle().
+		Height(m.ctx.MainContentHeight).
+		MaxHeight(m.ctx.MainContentHeight).
+		Render(mainContent))
 	s.WriteString("\n")
+	s.WriteString(m.viewFooter())
 
 	return s.String()
 }
 
+var (
+	taskStyle        = lipgloss.NewStyle().Faint(true)
+	taskSuccessStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	taskErrorStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+)
+
+func (m Model) viewFooter() string {
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		m.viewTaskStatus(),
+		m.help.View(m.currentKeyMap()),
+	)
+}
+
+// currentKeyMap returns the keys the help lists for the current section.
+func (m Model) currentKeyMap() help.KeyMap {
+	if currSection := m.getCurrSection(); currSection != nil && currSection.GetType() == workitemssection.SectionType {
+		return keys.WorkItemKeys
+	}
+	return keys.Keys
+}
+
+// viewTaskStatus renders the most relevant task on a single line: the
+// latest running task, or else the latest finished one.
+func (m Model) viewTaskStatus() string {
+	var latest *context.Task
+	for _, task := range m.tasks {
+		if latest == nil {
+			latest = &task
+			continue
+		}
+
+		isRunning := task.State == context.TaskStart
+		isLatestRunning := latest.State == context.TaskStart
+		if isRunning != isLatestRunning {
+			if isRunning {
+				latest = &task
+			}
+			continue
+		}
+		if task.StartTime.After(latest.StartTime) {
+			latest = &task
+		}
+	}
+
+	if latest == nil {
+		return ""
+	}
+
+	var status string
+	switch latest.State {
+	case context.TaskStart:
+		status = taskStyle.Render(fmt.Sprintf("%s%s", m.taskSpinner.View(), latest.StartText))
+	case context.TaskError:
+		status = taskErrorStyle.Render(fmt.Sprintf("✗ %s", strings.ReplaceAll(latest.Error.Error(), "\n", " ")))
+	default:
+		status = taskSuccessStyle.Render(fmt.Sprintf("✓ %s", latest.FinishedText))
+	}
+
+	return lipgloss.NewStyle().MaxWidth(m.ctx.ScreenWidth).Render(status)
+}
+
+func hasRunningTasks(tasks map[string]context.Task) bool {
+	for _, task := range tasks {
+		if task.State == context.TaskStart {
+			return true
+		}
+	}
+	return false
+}
+
+// syncMainContentSize gives the sections the space left over by the tab bar,
+// the footer and the preview.
+func (m *Model) syncMainContentSize() {
+	m.help.Width = m.ctx.ScreenWidth
+	footerHeight := lipgloss.Height(m.viewFooter())
+	m.ctx.MainContentHeight = max(0, m.ctx.ScreenHeight-tabsHeight-footerHeight)
+
+	m.ctx.PreviewWidth = 0
+	if m.ctx.PreviewOpen && m.ctx.Config != nil {
+		m.ctx.PreviewWidth = min(m.ctx.Config.Defaults.Preview.Width, m.ctx.ScreenWidth/2)
+	}
+	m.ctx.MainContentWidth = m.ctx.ScreenWidth - m.ctx.PreviewWidth
+}
+
+// onMainContentResize lets the sections lay themselves out again after the
+// space available to them changed.
+func (m *Model) onMainContentResize() tea.Cmd {
+	m.syncMainContentSize()
+	return m.updateAllSections(tea.WindowSizeMsg{
+		Width:  m.ctx.ScreenWidth,
+		Height: m.ctx.ScreenHeight,
+	})
+}
+
+func (m Model) viewTabs() string {
+	sections := m.getCurrentViewSections()
+	sectionTabs := make([]tabs.Tab, 0, len(sections))
+	for _, section := range sections {
+		sectionTabs = append(sectionTabs, tabs.Tab{
+			Title:     section.GetTitle(),
+			Count:     section.GetTotalCount(),
+			IsLoading: section.GetIsLoading(),
+		})
+	}
+
+	return tabs.View(sectionTabs, m.currSection, m.ctx.ScreenWidth)
+}
+
 func (m *Model) setCurrentViewSections(newSections []section.Section) {
-	m.prSection = newSections[0]
+	m.sections = newSections
+	m.currSection = 0
 }
 
-func (m *Model) updateCurrentSection(msg tea.Msg) (cmd tea.Cmd) {
+func (m *Model) updateCurrentSection(msg tea.Msg) tea.Cmd {
 	section := m.getCurrSection()
 	if section == nil {
 		return nil
@@ -189,14 +413,45 @@ func (m *Model) updateCurrentSection(msg tea.Msg) (cmd tea.Cmd) {
 	return m.updateSection(section.GetId(), section.GetType(), msg)
 }
 
+func (m *Model) updateAllSections(msg tea.Msg) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, section := range m.getCurrentViewSections() {
+		cmds = append(cmds, m.updateSection(section.GetId(), section.GetType(), msg))
+	}
+	return tea.Batch(cmds...)
+}
+
+// fetchAllViewSections creates a section of the configured type for every
+// section config and starts fetching their rows.
 func (m *Model) fetchAllViewSections() ([]section.Section, tea.Cmd) {
-	return prssection.FetchAllSections(m.ctx)
+	var fetchCmds []tea.Cmd
+	sections := make([]section.Section, 0)
+	for i, sectionConfig := range m.ctx.Config.GetSections() {
+		id := i + 1 // 0 is the search section
+		switch sectionConfig.Type {
+		case config.WorkItemsSectionType:
+			sectionModel := workitemssection.NewModel(id, m.ctx, sectionConfig, time.Now())
+			sections = append(sections, &sectionModel)
+			fetchCmds = append(fetchCmds, sectionModel.FetchNextPageSectionRows()...)
+		default:
+			sectionModel := prssection.NewModel(id, m.ctx, sectionConfig, time.Now())
+			sections = append(sections, &sectionModel)
+			fetchCmds = append(fetchCmds, sectionModel.FetchNextPageSectionRows()...)
+		}
+	}
+	return sections, tea.Batch(fetchCmds...)
 }
 
 func (m *Model) updateSection(id int, sType string, msg tea.Msg) (cmd tea.Cmd) {
-	var updatedSection section.Section
-	updatedSection, cmd = m.prSection.Update(msg)
-	m.prSection = updatedSection
+	for i, s := range m.sections {
+		if s.GetId() != id || s.GetType() != sType {
+			continue
+		}
+
+		var updatedSection section.Section
+		updatedSection, cmd = s.Update(msg)
+		m.sections[i] = updatedSection
+	}
 
 	return cmd
 }
@@ -0,0 +1,173 @@
+package workitemssection
+
+import (
+	"azdo-dash/context"
+	"azdo-dash/data"
+	"azdo-dash/ui/identitypicker"
+	"azdo-dash/ui/section"
+	gocontext "context"
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cli/browser"
+	"slices"
+	"time"
+)
+
+const setStateAction = "set_state"
+
+const stateOption = "state"
+
+type workItemUpdatedMsg struct {
+	WorkItem data.WorkItem
+}
+
+type statesFetchedMsg struct {
+	WorkItemID int
+	States     []string
+}
+
+func (m *Model) openInBrowser() tea.Cmd {
+	workItem := m.GetCurrWorkItem()
+	if workItem == nil {
+		return nil
+	}
+
+	url := m.Ctx.Client.WorkItemWebURL(*workItem)
+	task := context.Task{
+		Id:           fmt.Sprintf("open_work_item_%d_%s", workItem.ID, time.Now().String()),
+		StartText:    fmt.Sprintf("Opening work item #%d in the browser", workItem.ID),
+		FinishedText: fmt.Sprintf("Work item #%d has been opened in the browser", workItem.ID),
+	}
+
+	return m.RunTask(task, func() (tea.Msg, error) {
+		return nil, browser.OpenURL(url)
+	})
+}
+
+// fetchStates fetches the states the selected work item can move to, the
+// prompt to pick one is shown once they are known.
+func (m *Model) fetchStates() tea.Cmd {
+	workItem := m.GetCurrWorkItem()
+	if workItem == nil {
+		return nil
+	}
+
+	client := m.Ctx.Client
+	target := *workItem
+	task := context.Task{
+		Id:           fmt.Sprintf("fetch_work_item_states_%d_%s", target.ID, time.Now().String()),
+		StartText:    fmt.Sprintf("Fetching the states of %s", target.Type),
+		FinishedText: fmt.Sprintf("The states of %s have been fetched", target.Type),
+	}
+
+	return m.RunTask(task, func() (tea.Msg, error) {
+		states, err := client.FetchWorkItemStates(gocontext.Background(), target)
+		if err != nil {
+			return nil, err
+		}
+		return statesFetchedMsg{WorkItemID: target.ID, States: states}, nil
+	})
+}
+
+func (m *Model) onStatesFetched(msg statesFetchedMsg) {
+	workItem := m.GetCurrWorkItem()
+	// The user moved on while the states were fetched.
+	if workItem == nil || workItem.ID != msg.WorkItemID || len(msg.States) == 0 {
+		return
+	}
+
+	m.promptWorkItem = *workItem
+	m.ShowPrompt(
+		setStateAction,
+		fmt.Sprintf("Move work item #%d?", workItem.ID),
+		section.PromptOption{
+			Key:      "s",
+			Label:    stateOption,
+			Choices:  msg.States,
+			Selected: max(0, slices.Index(msg.States, workItem.State)),
+		},
+	)
+}
+
+func (m *Model) setState() tea.Cmd {
+	target := m.promptWorkItem
+	state := m.GetPromptOption(stateOption)
+	if state == "" || state == target.State {
+		return nil
+	}
+
+	client := m.Ctx.Client
+	task := context.Task{
+		Id:           fmt.Sprintf("set_work_item_state_%d_%s", target.ID, time.Now().String()),
+		StartText:    fmt.Sprintf("Moving work item #%d to %s", target.ID, state),
+		FinishedText: fmt.Sprintf("Work item #%d has been moved to %s", target.ID, state),
+	}
+
+	return m.RunTask(task, func() (tea.Msg, error) {
+		updated, err := client.SetWorkItemState(gocontext.Background(), target, state)
+		if err != nil {
+			return nil, err
+		}
+		return workItemUpdatedMsg{WorkItem: updated}, nil
+	})
+}
+
+func (m *Model) openAssigneePicker() {
+	workItem := m.GetCurrWorkItem()
+	if workItem == nil {
+		return
+	}
+
+	m.assigneeWorkItem = *workItem
+	m.assigneePicker = identitypicker.New("Assign to: ")
+}
+
+func (m *Model) updateAssigneePicker(msg tea.KeyMsg) tea.Cmd {
+	action, cmd := m.assigneePicker.Update(msg)
+	switch action {
+	case identitypicker.Search:
+		return m.SearchIdentities(m.assigneePicker.Query())
+	case identitypicker.Pick:
+		identity, _ := m.assigneePicker.Selected()
+		return m.assign(identity)
+	}
+	return cmd
+}
+
+func (m *Model) assign(identity data.User) tea.Cmd {
+	// Work items are assigned by the unique name, groups found by the
+	// search may only have a display name.
+	assignee := identity.UniqueName
+	if assignee == "" {
+		assignee = identity.DisplayName
+	}
+
+	client := m.Ctx.Client
+	target := m.assigneeWorkItem
+	task := context.Task{
+		Id:           fmt.Sprintf("assign_work_item_%d_%s", target.ID, time.Now().String()),
+		StartText:    fmt.Sprintf("Assigning work item #%d to %s", target.ID, identity.DisplayName),
+		FinishedText: fmt.Sprintf("Work item #%d has been assigned to %s", target.ID, identity.DisplayName),
+	}
+
+	return m.RunTask(task, func() (tea.Msg, error) {
+		updated, err := client.AssignWorkItem(gocontext.Background(), target, assignee)
+		if err != nil {
+			return nil, err
+		}
+		return workItemUpdatedMsg{WorkItem: updated}, nil
+	})
+}
+
+func (m Model) viewAssigneePicker() string {
+	return m.assigneePicker.View(m.Ctx.ScreenWidth, "enter search/assign • ↑/↓ select • esc cancel")
+}
+
+// onPromptConfirmed runs the action the user confirmed in the prompt.
+func (m *Model) onPromptConfirmed() tea.Cmd {
+	switch m.PromptConfirmationAction {
+	case setStateAction:
+		return m.setState()
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package workitemssection
+
+import (
+	"azdo-dash/data"
+	"azdo-dash/ui/section"
+	"github.com/charmbracelet/bubbles/table"
+	"strconv"
+	"strings"
+)
+
+// column describes a column of the work items table. Columns are at least
+// width wide and share the remaining width of the screen by their grow
+// factor, narrow screens hide the columns of the highest priority first.
+type column struct {
+	title    string
+	width    int
+	grow     int
+	priority int
+	value    func(workItem data.WorkItem) string
+}
+
+var columns = []column{
+	{title: "ID", width: 7, priority: 1, value: func(workItem data.WorkItem) string {
+		return strconv.Itoa(workItem.ID)
+	}},
+	{title: "Type", width: 10, priority: 4, value: func(workItem data.WorkItem) string {
+		return workItem.Type
+	}},
+	{title: "Title", width: 20, grow: 4, priority: 0, value: func(workItem data.WorkItem) string {
+		return workItem.Title
+	}},
+	{title: "State", width: 10, priority: 2, value: func(workItem data.WorkItem) string {
+		return workItem.State
+	}},
+	{title: "Assigned", width: 12, grow: 1, priority: 3, value: func(workItem data.WorkItem) string {
+		return workItem.AssignedTo
+	}},
+	{title: "Iteration", width: 12, grow: 1, priority: 5, value: func(workItem data.WorkItem) string {
+		return formatIterationPath(workItem.IterationPath)
+	}},
+}
+
+// formatIterationPath leaves out the project, the first segment of every
+// iteration path.
+func formatIterationPath(path string) string {
+	if _, iteration, ok := strings.Cut(path, `\`); ok {
+		return iteration
+	}
+	return path
+}
+
+// tableColumns returns the columns that fit in width and the indices of
+// them in columns.
+func tableColumns(width int) ([]table.Column, []int) {
+	layouts := make([]section.ColumnLayout, 0, len(columns))
+	for _, col := range columns {
+		layouts = append(layouts, section.ColumnLayout{
+			Title:    col.title,
+			Width:    col.width,
+			Grow:     col.grow,
+			Priority: col.priority,
+		})
+	}
+	return section.TableColumns(layouts, width)
+}
+
+// tableRows renders a row per work item with the cells of the shown columns.
+func tableRows(workItems []data.WorkItem, shown []int) []table.Row {
+	rows := make([]table.Row, 0, len(workItems))
+	for _, workItem := range workItems {
+		row := make(table.Row, 0, len(shown))
+		for _, index := range shown {
+			row = append(row, columns[index].value(workItem))
+		}
+		rows = append(rows, row)
+	}
+
+	return rows
+}
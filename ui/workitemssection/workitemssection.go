@@ -0,0 +1,290 @@
+package workitemssection
+
+import (
+	"azdo-dash/config"
+	"azdo-dash/constants"
+	"azdo-dash/context"
+	"azdo-dash/data"
+	"azdo-dash/ui/identitypicker"
+	"azdo-dash/ui/keys"
+	"azdo-dash/ui/section"
+	gocontext "context"
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"strings"
+	"time"
+)
+
+const SectionType = "workitems"
+
+// SectionWorkItemsFetchedMsg carries the result of the query of a section,
+// or the error it failed with.
+type SectionWorkItemsFetchedMsg struct {
+	WorkItems []data.WorkItem
+	Err       error
+	TaskId    string
+}
+
+// Model shows the work items returned by the WIQL query of the section.
+type Model struct {
+	section.Model
+	WorkItems      []data.WorkItem
+	FetchErr       error
+	assigneePicker identitypicker.Model
+	// promptWorkItem is the work item the shown prompt acts on.
+	promptWorkItem data.WorkItem
+	// assigneeWorkItem is the work item the assignee picker acts on.
+	assigneeWorkItem data.WorkItem
+}
+
+func NewModel(
+	id int,
+	ctx *context.ProgramContext,
+	cfg config.SectionConfig,
+	lastUpdated time.Time,
+) Model {
+	m := Model{}
+	m.Model = section.NewModel(
+		id,
+		ctx,
+		cfg,
+		SectionType,
+		lastUpdated,
+	)
+	m.Table = table.New(
+		table.WithFocused(true),
+		table.WithStyles(section.TableStyles),
+		table.WithKeyMap(keys.TableKeyMap()),
+	)
+	m.ResetRows()
+	m.syncTable()
+
+	return m
+}
+
+func (m Model) Update(msg tea.Msg) (section.Section, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	switch msg := msg.(type) {
+
+	case SectionWorkItemsFetchedMsg:
+		if m.LastFetchTaskId == msg.TaskId {
+			m.FetchErr = msg.Err
+			if msg.Err == nil {
+				m.WorkItems = msg.WorkItems
+			}
+			m.TotalCount = len(m.WorkItems)
+			m.IsLoading = false
+		}
+
+	case workItemUpdatedMsg:
+		m.updateWorkItem(msg.WorkItem)
+
+	case statesFetchedMsg:
+		m.onStatesFetched(msg)
+
+	case identitypicker.IdentitiesFoundMsg:
+		m.assigneePicker.SetIdentities(msg)
+
+	case tea.KeyMsg:
+		if m.IsPromptConfirmationShown {
+			if m.UpdatePrompt(msg) == section.PromptConfirmed {
+				cmds = append(cmds, m.onPromptConfirmed())
+			}
+			break
+		}
+		if m.assigneePicker.IsOpen {
+			cmds = append(cmds, m.updateAssigneePicker(msg))
+			break
+		}
+
+		switch {
+		case key.Matches(msg, keys.WorkItemKeys.OpenInBrowser):
+			cmds = append(cmds, m.openInBrowser())
+
+		case key.Matches(msg, keys.WorkItemKeys.SetState):
+			cmds = append(cmds, m.fetchStates())
+
+		case key.Matches(msg, keys.WorkItemKeys.Assign):
+			m.openAssigneePicker()
+
+		default:
+			var cmd tea.Cmd
+			m.Table, cmd = m.Table.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+	}
+
+	m.syncTable()
+
+	return &m, tea.Batch(cmds...)
+}
+
+func (m *Model) IsCapturingKeys() bool {
+	return m.Model.IsCapturingKeys() || m.assigneePicker.IsOpen
+}
+
+// GetCurrWorkItem returns the selected work item, or nil if there is none.
+func (m *Model) GetCurrWorkItem() *data.WorkItem {
+	cursor := m.Table.Cursor()
+	if cursor < 0 || cursor >= len(m.WorkItems) {
+		return nil
+	}
+	return &m.WorkItems[cursor]
+}
+
+// updateWorkItem replaces the row of a work item that changed.
+func (m *Model) updateWorkItem(workItem data.WorkItem) {
+	for i := range m.WorkItems {
+		if m.WorkItems[i].ID == workItem.ID {
+			m.WorkItems[i] = workItem
+		}
+	}
+}
+
+// syncTable fits the table into the main content area. Work items have no
+// preview, so the table takes the whole width of the screen.
+func (m *Model) syncTable() {
+	height := m.Ctx.MainContentHeight - section.TableHeaderHeight
+	if len(m.WorkItems) == 0 {
+		height--
+	}
+	if m.FetchErr != nil {
+		// A blank line precedes the error.
+		height -= 2
+	}
+	if m.IsPromptConfirmationShown {
+		height -= section.PromptHeight
+	}
+	if m.assigneePicker.IsOpen {
+		height -= identitypicker.Height
+	}
+	m.Table.SetHeight(max(1, height))
+	m.Table.SetWidth(m.Ctx.ScreenWidth)
+	// The table renders its rows with the new columns right away, so rows
+	// of the columns shown before must not outlive them.
+	tableColumns, shown := tableColumns(m.Ctx.ScreenWidth)
+	m.Table.SetRows(nil)
+	m.Table.SetColumns(tableColumns)
+	// The table moves its cursor to -1 while it has no rows.
+	cursor := max(0, min(m.Table.Cursor(), len(m.WorkItems)-1))
+	m.Table.SetRows(tableRows(m.WorkItems, shown))
+	m.Table.SetCursor(cursor)
+}
+
+func (m *Model) ResetRows() {
+	m.Table.SetCursor(0)
+	m.WorkItems = []data.WorkItem{}
+	m.FetchErr = nil
+	m.TotalCount = 0
+	m.IsLoading = false
+}
+
+// project returns the project the query runs in: the first of the section,
+// or else the first of the top level projects.
+func (m *Model) project() string {
+	projects := m.Config.Projects
+	if len(projects) == 0 && m.Ctx.Config != nil {
+		projects = m.Ctx.Config.Projects
+	}
+	if len(projects) == 0 {
+		return ""
+	}
+	return projects[0].Id
+}
+
+var (
+	errorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	emptyStyle = lipgloss.NewStyle().Faint(true).Padding(0, 1)
+)
+
+func (m Model) View() string {
+	s := strings.Builder{}
+
+	s.WriteString(m.Table.View())
+	s.WriteString("\n")
+	if len(m.WorkItems) == 0 {
+		if m.IsLoading {
+			s.WriteString(emptyStyle.Render("Loading work items..."))
+		} else {
+			s.WriteString(emptyStyle.Render("No work items"))
+		}
+		s.WriteString("\n")
+	}
+	if m.FetchErr != nil {
+		s.WriteString("\n")
+		s.WriteString(lipgloss.NewStyle().MaxWidth(m.Ctx.ScreenWidth).Render(
+			errorStyle.Render(strings.ReplaceAll(m.FetchErr.Error(), "\n", " ")),
+		))
+		s.WriteString("\n")
+	}
+
+	s.WriteString(m.ViewPrompt())
+	s.WriteString(m.viewAssigneePicker())
+
+	return s.String()
+}
+
+// FetchNextPageSectionRows runs the query of the section. The API returns
+// all work items of a query at once, so there is only a single page.
+func (m *Model) FetchNextPageSectionRows() []tea.Cmd {
+	if m == nil || m.IsLoading {
+		return nil
+	}
+	m.IsLoading = true
+
+	client := m.Ctx.Client
+	project := m.project()
+	title := m.Config.Title
+	query := m.Config.WIQL
+	limit := m.Ctx.Config.Defaults.WorkItemsLimit
+	fetchCtx := m.NewFetchContext()
+
+	id := m.Id
+	taskId := fmt.Sprintf("fetching_work_items_%d_%s", id, time.Now().String())
+	m.LastFetchTaskId = taskId
+	task := context.Task{
+		Id:           taskId,
+		StartText:    fmt.Sprintf(`Fetching work items for "%s"`, title),
+		FinishedText: fmt.Sprintf(`Work items for "%s" have been fetched`, title),
+		State:        context.TaskStart,
+	}
+	startCmd := m.Ctx.StartTask(task)
+
+	fetchCmd := func() tea.Msg {
+		workItems, err := client.QueryWorkItems(fetchCtx, project, query, limit)
+		// Refreshing cancels the fetch on purpose, the section waits for the
+		// fetch that replaced it.
+		if errors.Is(err, gocontext.Canceled) && fetchCtx.Err() != nil {
+			return constants.TaskFinishedMsg{
+				SectionId:    id,
+				SectionType:  SectionType,
+				TaskId:       taskId,
+				FinishedText: fmt.Sprintf(`Fetching work items for "%s" was cancelled`, title),
+			}
+		}
+		if err != nil {
+			err = fmt.Errorf("running the query of %q: %w", title, err)
+		}
+
+		// The message is delivered on errors too, so the section stops
+		// loading.
+		return constants.TaskFinishedMsg{
+			SectionId:   id,
+			SectionType: SectionType,
+			TaskId:      taskId,
+			Err:         err,
+			Msg: SectionWorkItemsFetchedMsg{
+				WorkItems: workItems,
+				Err:       err,
+				TaskId:    taskId,
+			},
+		}
+	}
+
+	return []tea.Cmd{startCmd, fetchCmd}
+}